@@ -0,0 +1,398 @@
+package hearth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Agent backend types accepted in the agents config section
+const (
+	AgentTypeClaude    = "claude"    // the claude CLI, configured by the caller section
+	AgentTypeCLI       = "cli"       // any CLI, with an argv template
+	AgentTypeOpenAI    = "openai"    // OpenAI-compatible chat completions endpoint
+	AgentTypeAnthropic = "anthropic" // Anthropic Messages API
+)
+
+// AgentClaude is the built-in agent name, always available, backed by the caller section
+const AgentClaude = "claude"
+
+// NewAgentCaller creates the caller for a named agent from the workspace config
+// An empty name selects the workspace default (the agent key, else claude);
+// an empty model falls back to the agent's model, then the workspace model
+func NewAgentCaller(cfg *Config, name, model string) (AgentCaller, error) {
+	if name == "" {
+		name = cfg.Agent
+	}
+	if name == "" {
+		name = AgentClaude
+	}
+
+	agent, ok := cfg.Agents[name]
+	if !ok {
+		if name != AgentClaude {
+			return nil, fmt.Errorf("unknown agent %q", name)
+		}
+		agent = AgentConfig{Type: AgentTypeClaude}
+	}
+
+	if model == "" {
+		model = agent.Model
+	}
+	if model == "" {
+		model = cfg.Model
+	}
+	timeout := time.Duration(cfg.Timeout)
+
+	// A missing key fails the agent's calls, not every command that loads the config
+	if env := agent.missingAPIKey(); env != "" {
+		return &missingKeyCaller{agent: name, env: env}, nil
+	}
+
+	switch agent.Type {
+	case AgentTypeClaude:
+		caller := NewClaudeCaller(cfg)
+		if agent.Command != "" {
+			caller.Command = agent.Command
+		}
+		if agent.Args != nil {
+			caller.Args = agent.Args
+		}
+		caller.Model = model
+		return caller, nil
+	case AgentTypeCLI:
+		return &CLIAgent{Command: agent.Command, Args: agent.Args, Model: model, Timeout: timeout}, nil
+	case AgentTypeOpenAI:
+		return &OpenAIAgent{URL: agent.URL, APIKey: os.Getenv(agent.apiKeyEnv()), Model: model, Timeout: timeout}, nil
+	case AgentTypeAnthropic:
+		return &AnthropicAgent{URL: agent.URL, APIKey: os.Getenv(agent.apiKeyEnv()), Model: model, MaxTokens: agent.MaxTokens, Timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("agent %q has unknown type %q", name, agent.Type)
+	}
+}
+
+// missingKeyCaller stands in for an API backend whose key is not set: every call fails,
+// so the config stays valid for workspaces and tasks that never use the agent
+type missingKeyCaller struct {
+	agent string
+	env   string
+}
+
+func (c *missingKeyCaller) Call(prompt, workDir string) (string, error) {
+	return "", fmt.Errorf("agent %q: $%s is not set (api_key_env names the variable holding the API key)", c.agent, c.env)
+}
+
+// AgentFactory creates the caller for an agent selection
+type AgentFactory func(selection AgentSelection) (AgentCaller, error)
+
+// Call kinds with their own workspace defaults (the execute and summary config sections)
+const (
+	CallKindExecute = "execute"
+	CallKindSummary = "summary"
+	CallKindReview  = "review" // review settings take precedence over task choices
+)
+
+// AgentSelection is the agent backend and model chosen for a call
+type AgentSelection struct {
+	Agent string
+	Model string
+}
+
+// String formats the selection as "agent (model)"; an empty agent is the default claude backend
+func (s AgentSelection) String() string {
+	agent := s.Agent
+	if agent == "" {
+		agent = AgentClaude
+	}
+	if s.Model == "" {
+		return agent
+	}
+	return fmt.Sprintf("%s (%s)", agent, s.Model)
+}
+
+// SelectAgent chooses the agent and model for a call about a task
+// Precedence: the task's escalation rung, the task's own choice, inherited from the
+// nearest ancestor that made one, then the workspace default for the call kind,
+// then the workspace agent and model.
+// Agent and model are picked independently, but a model chosen at a lower level than
+// the agent is dropped in favour of that agent's own model.
+func SelectAgent(taskID string, tasks map[string]*Task, cfg *Config, kind string) AgentSelection {
+	rung := 0
+	if task := tasks[taskID]; task != nil && kind == CallKindExecute {
+		rung = task.Rung
+	}
+	return selectAgent(taskID, tasks, cfg, kind, rung)
+}
+
+// selectAgent is SelectAgent for a given escalation rung
+func selectAgent(taskID string, tasks map[string]*Task, cfg *Config, kind string, rung int) AgentSelection {
+	var levels []AgentSelection
+	if kind == CallKindReview {
+		levels = append(levels, AgentSelection{Agent: cfg.Review.Agent, Model: cfg.Review.Model})
+	}
+	for task := tasks[taskID]; task != nil; task = parentOf(task, tasks) {
+		levels = append(levels, AgentSelection{Agent: task.Agent, Model: task.Model})
+	}
+	switch kind {
+	case CallKindExecute:
+		levels = append(levels, AgentSelection(cfg.Execute))
+	case CallKindSummary:
+		levels = append(levels, AgentSelection(cfg.Summary))
+	}
+
+	agentLevel, modelLevel := -1, -1
+	var selection AgentSelection
+	for i, level := range levels {
+		if agentLevel < 0 && level.Agent != "" {
+			agentLevel, selection.Agent = i, level.Agent
+		}
+		if modelLevel < 0 && level.Model != "" {
+			modelLevel, selection.Model = i, level.Model
+		}
+	}
+	if agentLevel >= 0 && modelLevel > agentLevel {
+		selection.Model = ""
+	}
+
+	// Resolve workspace defaults so the recorded selection is what actually ran
+	if selection.Agent == "" {
+		selection.Agent = cfg.Agent
+	}
+	if selection.Agent == "" {
+		selection.Agent = AgentClaude
+	}
+	if selection.Model == "" {
+		selection.Model = cfg.Agents[selection.Agent].Model
+	}
+	if selection.Model == "" {
+		selection.Model = cfg.Model
+	}
+
+	// Escalated calls run on their rung of the ladder
+	if rung > 0 && rung <= len(cfg.Escalation) {
+		step := cfg.Escalation[rung-1]
+		if step.Agent != "" {
+			selection = AgentSelection{Agent: step.Agent, Model: step.Model}
+			if selection.Model == "" {
+				selection.Model = cfg.Agents[step.Agent].Model
+			}
+			if selection.Model == "" {
+				selection.Model = cfg.Model
+			}
+		} else {
+			selection.Model = step.Model
+		}
+	}
+	return selection
+}
+
+// ============================================================================
+// CLI - Any command line agent
+// ============================================================================
+
+// CLIAgent runs an arbitrary agent CLI in the task's working directory
+// Each arg is a text/template with .Prompt, .Model and .WorkDir; args that render
+// empty are dropped. If no arg uses .Prompt, the prompt is appended as the last arg.
+type CLIAgent struct {
+	Command string
+	Args    []string
+	Model   string
+	Timeout time.Duration // 0 = no timeout
+	Env     []string      // extra KEY=VALUE environment variables
+	Clock   Clock         // timestamps the call (nil = the wall clock)
+}
+
+func (c *CLIAgent) Call(prompt, workDir string) (string, error) {
+	result, err := c.CallDetailed(prompt, workDir)
+	if err != nil {
+		return "", err
+	}
+	return result.Output, nil
+}
+
+// CallDetailed renders the argv template and runs the command
+func (c *CLIAgent) CallDetailed(prompt, workDir string) (*CallResult, error) {
+	argv, err := c.argv(prompt, workDir)
+	if err != nil {
+		return &CallResult{ExitCode: -1}, err
+	}
+	return runCommand(c.Clock, c.Command, argv, workDir, c.Env, c.Timeout)
+}
+
+func (c *CLIAgent) argv(prompt, workDir string) ([]string, error) {
+	data := struct{ Prompt, Model, WorkDir string }{prompt, c.Model, workDir}
+
+	var argv []string
+	usesPrompt := false
+	for _, arg := range c.Args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument template %q: %w", arg, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("invalid argument template %q: %w", arg, err)
+		}
+		if strings.Contains(arg, ".Prompt") {
+			usesPrompt = true
+		}
+		if b.Len() > 0 {
+			argv = append(argv, b.String())
+		}
+	}
+
+	if !usesPrompt {
+		argv = append(argv, prompt)
+	}
+	return argv, nil
+}
+
+// ============================================================================
+// HTTP - Chat APIs (text in, text out; they cannot edit the workspace)
+// ============================================================================
+
+// OpenAIAgent calls an OpenAI-compatible chat completions endpoint
+type OpenAIAgent struct {
+	URL     string // base URL, e.g. https://api.openai.com/v1 or http://localhost:11434/v1
+	APIKey  string // sent as a bearer token when set
+	Model   string
+	Timeout time.Duration
+	Client  *http.Client // defaults to http.DefaultClient
+}
+
+func (a *OpenAIAgent) Call(prompt, workDir string) (string, error) {
+	request := map[string]interface{}{
+		"model":    a.Model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	}
+	headers := map[string]string{}
+	if a.APIKey != "" {
+		headers["Authorization"] = "Bearer " + a.APIKey
+	}
+
+	var response struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	url := strings.TrimSuffix(a.URL, "/") + "/chat/completions"
+	if err := postJSON(a.Client, a.Timeout, url, headers, request, &response); err != nil {
+		return "", err
+	}
+
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("chat completion returned no choices")
+	}
+	return response.Choices[0].Message.Content, nil
+}
+
+// Defaults for the Anthropic Messages API
+const (
+	DefaultAnthropicURL       = "https://api.anthropic.com"
+	DefaultAnthropicMaxTokens = 8192
+	anthropicVersion          = "2023-06-01"
+)
+
+// AnthropicAgent calls the Anthropic Messages API
+type AnthropicAgent struct {
+	URL       string // base URL (default DefaultAnthropicURL)
+	APIKey    string
+	Model     string
+	MaxTokens int // default DefaultAnthropicMaxTokens
+	Timeout   time.Duration
+	Client    *http.Client // defaults to http.DefaultClient
+}
+
+func (a *AnthropicAgent) Call(prompt, workDir string) (string, error) {
+	maxTokens := a.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = DefaultAnthropicMaxTokens
+	}
+	request := map[string]interface{}{
+		"model":      a.Model,
+		"max_tokens": maxTokens,
+		"messages":   []map[string]string{{"role": "user", "content": prompt}},
+	}
+	headers := map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var response struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	base := a.URL
+	if base == "" {
+		base = DefaultAnthropicURL
+	}
+	if err := postJSON(a.Client, a.Timeout, strings.TrimSuffix(base, "/")+"/v1/messages", headers, request, &response); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), nil
+}
+
+// postJSON sends a JSON request and decodes a JSON response, failing on non-2xx statuses
+func postJSON(client *http.Client, timeout time.Duration, url string, headers map[string]string, request, response interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request to %s failed: %s: %s", url, resp.Status, truncate(strings.TrimSpace(string(data)), 500))
+	}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package hearth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCLIAgent_ArgvTemplate tests that args are rendered and empty ones dropped
+func TestCLIAgent_ArgvTemplate(t *testing.T) {
+	agent := &CLIAgent{
+		Command: "echo",
+		Args:    []string{"-n", "{{if .Model}}--model={{.Model}}{{end}}", "task: {{.Prompt}}"},
+	}
+
+	output, err := agent.Call("fix the bug", t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "task: fix the bug", output)
+
+	agent.Model = "gpt-5"
+	output, err = agent.Call("fix the bug", t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "--model=gpt-5 task: fix the bug", output)
+
+	// Without a .Prompt placeholder the prompt is appended
+	agent.Args = []string{"-n"}
+	output, err = agent.Call("fix the bug", t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "fix the bug", output)
+}
+
+// TestOpenAIAgent tests the chat completions request and response handling
+func TestOpenAIAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var request struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "llama3", request.Model)
+		assert.Equal(t, "user", request.Messages[0].Role)
+
+		if request.Messages[0].Content == "fail" {
+			http.Error(w, `{"error":"overloaded"}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"echo: ` + request.Messages[0].Content + `"}}]}`))
+	}))
+	defer server.Close()
+
+	agent := &OpenAIAgent{URL: server.URL + "/v1", APIKey: "secret", Model: "llama3"}
+	output, err := agent.Call("hello", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: hello", output)
+
+	_, err = agent.Call("fail", "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Contains(t, err.Error(), "overloaded")
+}
+
+// TestAnthropicAgent tests the Messages API request and response handling
+func TestAnthropicAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("x-api-key"))
+		assert.Equal(t, anthropicVersion, r.Header.Get("anthropic-version"))
+
+		var request struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "claude-sonnet-4-5", request.Model)
+		assert.Equal(t, DefaultAnthropicMaxTokens, request.MaxTokens)
+
+		w.Write([]byte(`{"content":[{"type":"text","text":"Hello "},{"type":"tool_use","id":"x"},{"type":"text","text":"world"}]}`))
+	}))
+	defer server.Close()
+
+	agent := &AnthropicAgent{URL: server.URL, APIKey: "secret", Model: "claude-sonnet-4-5"}
+	output, err := agent.Call("hi", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello world", output)
+}
+
+// TestNewAgentCaller tests backend selection from the workspace config
+func TestNewAgentCaller(t *testing.T) {
+	t.Setenv("LOCAL_KEY", "from-env")
+
+	cfg := DefaultConfig()
+	cfg.Model = "sonnet"
+	cfg.Agents = map[string]AgentConfig{
+		"local": {Type: AgentTypeOpenAI, URL: "http://localhost:11434/v1", APIKeyEnv: "LOCAL_KEY", Model: "llama3"},
+		"codex": {Type: AgentTypeCLI, Command: "codex", Args: []string{"exec", "{{.Prompt}}"}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	caller, err := NewAgentCaller(cfg, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "sonnet", caller.(*DefaultClaudeCaller).Model)
+
+	cfg.Agent = "local"
+	caller, err = NewAgentCaller(cfg, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, &OpenAIAgent{URL: "http://localhost:11434/v1", APIKey: "from-env", Model: "llama3", Timeout: time.Duration(cfg.Timeout)}, caller)
+
+	caller, err = NewAgentCaller(cfg, "codex", "o3")
+	assert.NoError(t, err)
+	assert.Equal(t, "o3", caller.(*CLIAgent).Model)
+
+	_, err = NewAgentCaller(cfg, "missing", "")
+	assert.Error(t, err)
+
+	cfg.Agent = "missing"
+	cfg.Agents["bad"] = AgentConfig{Type: "telepathy"}
+	err = cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `agent "missing"`)
+	assert.Contains(t, err.Error(), "agents.bad.type")
+}
+
+// TestSelectAgent tests task choices, inheritance and per-kind workspace defaults
+func TestSelectAgent(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Model = "sonnet"
+	cfg.Summary = KindConfig{Model: "haiku"}
+	cfg.Agents = map[string]AgentConfig{
+		"local": {Type: AgentTypeOpenAI, URL: "http://localhost:11434/v1", Model: "llama3"},
+	}
+
+	tasks := map[string]*Task{
+		"ROOT":  {ID: "ROOT", Model: "opus"},
+		"A":     {ID: "A", ParentID: strPtr("ROOT")},
+		"B":     {ID: "B", ParentID: strPtr("ROOT"), Agent: "local"},
+		"PLAIN": {ID: "PLAIN"},
+	}
+
+	// Inherited from the parent, for both kinds
+	assert.Equal(t, AgentSelection{Agent: "claude", Model: "opus"}, SelectAgent("A", tasks, cfg, CallKindExecute))
+	assert.Equal(t, AgentSelection{Agent: "claude", Model: "opus"}, SelectAgent("A", tasks, cfg, CallKindSummary))
+
+	// An agent chosen below the model's level uses its own model
+	assert.Equal(t, AgentSelection{Agent: "local", Model: "llama3"}, SelectAgent("B", tasks, cfg, CallKindExecute))
+
+	// Workspace defaults per kind
+	assert.Equal(t, AgentSelection{Agent: "claude", Model: "sonnet"}, SelectAgent("PLAIN", tasks, cfg, CallKindExecute))
+	assert.Equal(t, AgentSelection{Agent: "claude", Model: "haiku"}, SelectAgent("PLAIN", tasks, cfg, CallKindSummary))
+
+	// An escalation rung naming an agent without a model uses the workspace model
+	cfg.Agents["codex"] = AgentConfig{Type: AgentTypeCLI, Command: "codex"}
+	cfg.Escalation = []KindConfig{{Agent: "local"}, {Agent: "codex"}}
+	tasks["PLAIN"].Rung = 1
+	assert.Equal(t, AgentSelection{Agent: "local", Model: "llama3"}, SelectAgent("PLAIN", tasks, cfg, CallKindExecute))
+	tasks["PLAIN"].Rung = 2
+	assert.Equal(t, AgentSelection{Agent: "codex", Model: "sonnet"}, SelectAgent("PLAIN", tasks, cfg, CallKindExecute))
+}
@@ -0,0 +1,221 @@
+package hearth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Cassette holds recorded agent interactions for offline replay
+type Cassette struct {
+	IDStrategy   string        `json:"id_strategy,omitempty"` // ids.strategy of the recorded workspace
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded agent call
+type Interaction struct {
+	PromptHash string          `json:"prompt_hash"`
+	Prompt     string          `json:"prompt"`
+	Response   string          `json:"response"`
+	Error      string          `json:"error,omitempty"`
+	Events     json.RawMessage `json:"events,omitempty"` // events the agent appended (events.json format)
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+
+	return &cassette, nil
+}
+
+// Save writes the cassette to path
+func (c *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// CheckReplayable reports why the cassette cannot be replayed strictly in a workspace using
+// the given ids.strategy: prompts contain task IDs, so they must be generated the same way
+func (c *Cassette) CheckReplayable(strategy string) error {
+	if !DeterministicIDs(strategy) {
+		return fmt.Errorf("strict replay needs deterministic task IDs: set ids.strategy to sequential, hierarchical or hash (workspace uses %s)", strategyName(strategy))
+	}
+	if c.IDStrategy != "" && c.IDStrategy != strategy {
+		return fmt.Errorf("cassette was recorded with ids.strategy %s, workspace uses %s", strategyName(c.IDStrategy), strategyName(strategy))
+	}
+	return nil
+}
+
+// RecordingCaller forwards calls to a real caller and records each prompt, response
+// and the events the agent appended to the workspace log (e.g. via `hearth add`)
+// The cassette is saved after every call so partial sessions are kept
+type RecordingCaller struct {
+	Caller       AgentCaller
+	Path         string // cassette file
+	WorkspaceDir string // workspace whose event log is watched
+	IDStrategy   string // ids.strategy of the workspace, stored for replay
+
+	cassette *Cassette // shared with the callers created by Wrap
+}
+
+// Wrap returns a caller recording calls to caller into the same cassette
+// Use it with WithCallerWrapper so every agent backend of a session is recorded
+func (c *RecordingCaller) Wrap(caller AgentCaller) AgentCaller {
+	if c.cassette == nil {
+		c.cassette = &Cassette{}
+	}
+	return &RecordingCaller{Caller: caller, Path: c.Path, WorkspaceDir: c.WorkspaceDir, IDStrategy: c.IDStrategy, cassette: c.cassette}
+}
+
+func (c *RecordingCaller) Call(prompt, workDir string) (string, error) {
+	if c.cassette == nil {
+		c.cassette = &Cassette{}
+	}
+	c.cassette.IDStrategy = c.IDStrategy
+
+	before, err := readRawEvents(c.WorkspaceDir)
+	if err != nil {
+		return "", err
+	}
+
+	response, callErr := c.Caller.Call(prompt, workDir)
+
+	after, err := readRawEvents(c.WorkspaceDir)
+	if err != nil {
+		return "", err
+	}
+
+	interaction := Interaction{
+		PromptHash: PromptHash(prompt),
+		Prompt:     prompt,
+		Response:   response,
+	}
+	if callErr != nil {
+		interaction.Error = callErr.Error()
+	}
+	if len(after) > len(before) {
+		interaction.Events, err = json.Marshal(after[len(before):])
+		if err != nil {
+			return "", fmt.Errorf("failed to record events: %w", err)
+		}
+	}
+
+	c.cassette.Interactions = append(c.cassette.Interactions, interaction)
+	if err := c.cassette.Save(c.Path); err != nil {
+		return "", err
+	}
+
+	return response, callErr
+}
+
+// ReplayCaller answers calls from a cassette, in order, and re-applies the
+// recorded side-effect events to the workspace log as the agent did
+type ReplayCaller struct {
+	Cassette     *Cassette
+	WorkspaceDir string // workspace to append recorded events to
+	Strict       bool   // fail when a prompt differs from the recording
+
+	next int
+}
+
+// NewReplayCaller loads a cassette for replay
+func NewReplayCaller(path, workspaceDir string, strict bool) (*ReplayCaller, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayCaller{Cassette: cassette, WorkspaceDir: workspaceDir, Strict: strict}, nil
+}
+
+// Remaining returns the number of interactions not yet replayed
+func (c *ReplayCaller) Remaining() int {
+	return len(c.Cassette.Interactions) - c.next
+}
+
+func (c *ReplayCaller) Call(prompt, workDir string) (string, error) {
+	if c.next >= len(c.Cassette.Interactions) {
+		return "", fmt.Errorf("cassette exhausted after %d interactions", len(c.Cassette.Interactions))
+	}
+	interaction := c.Cassette.Interactions[c.next]
+	c.next++
+
+	if c.Strict && interaction.PromptHash != PromptHash(prompt) {
+		return "", fmt.Errorf("prompt mismatch at interaction %d (recorded %s, got %s)",
+			c.next, interaction.PromptHash, PromptHash(prompt))
+	}
+
+	if len(interaction.Events) > 0 {
+		if err := appendRawEvents(c.WorkspaceDir, interaction.Events); err != nil {
+			return "", err
+		}
+	}
+
+	if interaction.Error != "" {
+		return "", fmt.Errorf("%s", interaction.Error)
+	}
+	return interaction.Response, nil
+}
+
+// readRawEvents returns the workspace's persisted events without decoding them
+func readRawEvents(workspaceDir string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(filepath.Join(workspaceDir, ".hearth", "events.json"))
+	if os.IsNotExist(err) || len(data) == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	var events []json.RawMessage
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+	return events, nil
+}
+
+// appendRawEvents appends recorded events to the workspace log through the file repository
+// (the same path `hearth add` takes, minus validation - they were valid when recorded)
+func appendRawEvents(workspaceDir string, data json.RawMessage) error {
+	codec, err := New()
+	if err != nil {
+		return err
+	}
+
+	events, err := codec.Engine().UnmarshalEvents(data)
+	if err != nil {
+		return fmt.Errorf("failed to decode recorded events: %w", err)
+	}
+
+	repo, err := NewFileRepository(workspaceDir)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		if err := repo.Add(codec.Engine(), event); err != nil {
+			return fmt.Errorf("failed to replay event: %w", err)
+		}
+	}
+
+	return nil
+}
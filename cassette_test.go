@@ -0,0 +1,115 @@
+package hearth
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// subtaskAgent simulates an agent that runs `hearth add` for the root task
+type subtaskAgent struct {
+	workspaceDir string
+}
+
+func (a *subtaskAgent) Call(prompt, workDir string) (string, error) {
+	if strings.Contains(prompt, "CURRENT TASK ID: ROOT\n") {
+		h, err := NewHearth(a.workspaceDir)
+		if err != nil {
+			return "", err
+		}
+		now := time.Now()
+		_ = h.Process(&TaskCreated{TaskID: "C1", Title: "First step", ParentID: strPtr("ROOT"), Time: now})
+		_ = h.Process(&TaskCreated{TaskID: "C2", Title: "Second step", ParentID: strPtr("ROOT"), Time: now.Add(time.Second)})
+		return "Split into two subtasks", nil
+	}
+	return "done", nil
+}
+
+// runSession creates the root task in a fresh workspace and runs it with caller
+func runSession(t *testing.T, workspaceDir string, caller AgentCaller) *Hearth {
+	h, err := New(WithWorkspace(workspaceDir), WithCaller(caller), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "ROOT", Title: "Root", Description: "Plan and do", Time: time.Now()}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: time.Now()}))
+	return h
+}
+
+// TestCassette_RecordAndReplay tests that a recorded session replays offline with the same outcome
+func TestCassette_RecordAndReplay(t *testing.T) {
+	cassettePath := filepath.Join(t.TempDir(), "session.json")
+
+	// Record against the simulated agent
+	recordDir := t.TempDir()
+	recorder := &RecordingCaller{Caller: &subtaskAgent{workspaceDir: recordDir}, Path: cassettePath, WorkspaceDir: recordDir}
+	recorded := runSession(t, recordDir, recorder)
+
+	cassette, err := LoadCassette(cassettePath)
+	assert.NoError(t, err)
+	assert.Len(t, cassette.Interactions, 4) // ROOT, C1, C2, ROOT summary
+	assert.NotEmpty(t, cassette.Interactions[0].Events)
+	assert.Empty(t, cassette.Interactions[1].Events)
+
+	// Replay in a fresh workspace without the agent
+	replayDir := t.TempDir()
+	replayer, err := NewReplayCaller(cassettePath, replayDir, true)
+	assert.NoError(t, err)
+	replayed := runSession(t, replayDir, replayer)
+
+	assert.Equal(t, 0, replayer.Remaining())
+	assert.Len(t, replayed.GetTasks(), 3)
+	for id, task := range recorded.GetTasks() {
+		assert.Equal(t, task.Status, replayed.GetTask(id).Status, id)
+	}
+	assert.Equal(t, "completed", replayed.GetTask("ROOT").Status)
+}
+
+// TestReplayCaller_StrictMismatch tests that strict replay detects prompt drift
+func TestReplayCaller_StrictMismatch(t *testing.T) {
+	replayer := &ReplayCaller{
+		Cassette: &Cassette{Interactions: []Interaction{{PromptHash: PromptHash("old prompt"), Response: "r"}}},
+		Strict:   true,
+	}
+
+	_, err := replayer.Call("new prompt", "")
+	assert.ErrorContains(t, err, "prompt mismatch")
+
+	_, err = replayer.Call("old prompt", "")
+	assert.ErrorContains(t, err, "cassette exhausted")
+}
+
+// TestRecordingCaller_WrapsEveryBackend tests that recording through WithCallerWrapper captures
+// calls to per-task agents from the configured backends, not just the default caller
+func TestRecordingCaller_WrapsEveryBackend(t *testing.T) {
+	dir := t.TempDir()
+	cassettePath := filepath.Join(t.TempDir(), "session.json")
+	cfg := DefaultConfig()
+	cfg.IDs.Strategy = IDStrategySequential
+	cfg.Agent = "main"
+	cfg.Agents = map[string]AgentConfig{
+		"main":  {Type: AgentTypeCLI, Command: "echo", Args: []string{"main"}},
+		"other": {Type: AgentTypeCLI, Command: "echo", Args: []string{"other"}},
+	}
+
+	recorder := &RecordingCaller{Path: cassettePath, WorkspaceDir: dir, IDStrategy: cfg.IDs.Strategy}
+	h, err := New(WithWorkspace(dir), WithConfig(cfg), WithCallerWrapper(recorder.Wrap), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "Default agent", Time: time.Now()}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-2", Title: "Other agent", Agent: "other", Time: time.Now()}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: time.Now()}))
+
+	cassette, err := LoadCassette(cassettePath)
+	assert.NoError(t, err)
+	assert.Equal(t, IDStrategySequential, cassette.IDStrategy)
+	if assert.Len(t, cassette.Interactions, 2) {
+		assert.True(t, strings.HasPrefix(cassette.Interactions[0].Response, "main "))
+		assert.True(t, strings.HasPrefix(cassette.Interactions[1].Response, "other "))
+	}
+
+	assert.NoError(t, cassette.CheckReplayable(IDStrategySequential))
+	assert.ErrorContains(t, cassette.CheckReplayable(IDStrategyHierarchical), "recorded with ids.strategy sequential")
+	assert.ErrorContains(t, cassette.CheckReplayable(""), "deterministic task IDs")
+}
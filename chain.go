@@ -0,0 +1,289 @@
+package hearth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Chained is implemented by events that carry a hash chain link
+type Chained interface {
+	Hashes() (prev, hash string)
+	SetHashes(prev, hash string)
+}
+
+// Hashes returns the previous event's hash and this event's hash
+func (m *EventMeta) Hashes() (string, string) { return m.PrevHash, m.Hash }
+
+// SetHashes sets the previous event's hash and this event's hash
+func (m *EventMeta) SetHashes(prev, hash string) { m.PrevHash, m.Hash = prev, hash }
+
+// EventHash computes an event's hash: sha256 of its type and JSON contents, which
+// include the previous event's hash (the event's own Hash is left out)
+func EventHash(event atmos.Event) (string, error) {
+	chained, ok := event.(Chained)
+	if !ok {
+		return "", fmt.Errorf("event %s cannot be hashed", event.Type())
+	}
+	prev, hash := chained.Hashes()
+	chained.SetHashes(prev, "")
+	data, err := json.Marshal(event)
+	chained.SetHashes(prev, hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", event.Type(), err)
+	}
+
+	sum := sha256.New()
+	sum.Write([]byte(event.Type()))
+	sum.Write([]byte{0})
+	sum.Write(data)
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// chainEvent links an event to the one stored before it
+func chainEvent(event atmos.Event, existing []atmos.Event) {
+	chained, ok := event.(Chained)
+	if !ok {
+		return
+	}
+	prev := ""
+	if len(existing) > 0 {
+		if last, ok := existing[len(existing)-1].(Chained); ok {
+			_, prev = last.Hashes()
+		}
+	}
+	chained.SetHashes(prev, "")
+	if hash, err := EventHash(event); err == nil {
+		chained.SetHashes(prev, hash)
+	}
+}
+
+// ============================================================================
+// LOG HEAD - The last event's hash, kept beside the log to detect truncation
+// ============================================================================
+
+// LogHead identifies the last event written to the log
+type LogHead struct {
+	Seq  int64
+	Hash string
+}
+
+// LogHeadPath returns the file recording the log head
+func LogHeadPath(workspaceDir string) string {
+	return filepath.Join(workspaceDir, ".hearth", "events.head")
+}
+
+// writeLogHead records the last of events as the log head
+func writeLogHead(path string, events []atmos.Event) error {
+	var head LogHead
+	if len(events) > 0 {
+		last := events[len(events)-1]
+		head.Seq = NewEventRecord(last).Seq
+		if chained, ok := last.(Chained); ok {
+			_, head.Hash = chained.Hashes()
+		}
+	}
+	data, err := json.Marshal(head)
+	if err != nil {
+		return fmt.Errorf("failed to encode log head: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write log head: %w", err)
+	}
+	return nil
+}
+
+// readLogHead reads the recorded log head (nil when there is none)
+func readLogHead(path string) (*LogHead, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log head: %w", err)
+	}
+	var head LogHead
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, fmt.Errorf("failed to parse log head: %w", err)
+	}
+	return &head, nil
+}
+
+// ============================================================================
+// VERIFICATION - Detect tampering, truncation and reordering
+// ============================================================================
+
+// LogProblem is the first inconsistency found in the event log
+type LogProblem struct {
+	Index  int   // position in events.json, from 0
+	Seq    int64 // sequence number of the event (0 when unknown)
+	Type   string
+	Reason string
+}
+
+func (p *LogProblem) String() string {
+	if p.Seq > 0 {
+		return fmt.Sprintf("event #%d (%s, entry %d): %s", p.Seq, p.Type, p.Index+1, p.Reason)
+	}
+	if p.Type != "" {
+		return fmt.Sprintf("entry %d (%s): %s", p.Index+1, p.Type, p.Reason)
+	}
+	return fmt.Sprintf("entry %d: %s", p.Index+1, p.Reason)
+}
+
+// LogReport is the result of verifying the event log
+type LogReport struct {
+	Events   int         // entries in the log
+	Unhashed int         // leading events written before hash chaining
+	Head     *LogHead    // recorded head (nil when the log predates it)
+	Problem  *LogProblem // first problem found (nil = the log is intact)
+}
+
+// VerifyLog checks the workspace's event log: every event must decode, be numbered
+// one after the other, link to the previous event's hash and match its own hash,
+// and the last event must be the recorded head
+func VerifyLog(workspaceDir string) (*LogReport, error) {
+	data, err := os.ReadFile(filepath.Join(workspaceDir, ".hearth", "events.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	report := &LogReport{}
+	if report.Head, err = readLogHead(LogHeadPath(workspaceDir)); err != nil {
+		return nil, err
+	}
+
+	var wrappers []struct {
+		Type string
+		Data json.RawMessage
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &wrappers); err != nil {
+			report.Problem = &LogProblem{Reason: fmt.Sprintf("events.json is not valid: %v", err)}
+			return report, nil
+		}
+	}
+	report.Events = len(wrappers)
+
+	codec, err := New()
+	if err != nil {
+		return nil, err
+	}
+
+	var prevSeq int64
+	prevHash := ""
+	chained := false
+	for i, wrapper := range wrappers {
+		fail := func(seq int64, format string, args ...any) (*LogReport, error) {
+			report.Problem = &LogProblem{Index: i, Seq: seq, Type: wrapper.Type, Reason: fmt.Sprintf(format, args...)}
+			return report, nil
+		}
+
+		event, err := decodeEvent(codec.engine, wrapper.Type, wrapper.Data)
+		if err != nil {
+			return fail(0, "%v", err)
+		}
+		record := NewEventRecord(event)
+		prev, hash := event.(Chained).Hashes()
+
+		if record.Seq != 0 && record.Seq != prevSeq+1 {
+			return fail(record.Seq, "expected event #%d (events were removed or reordered)", prevSeq+1)
+		}
+		if record.Seq != 0 {
+			prevSeq = record.Seq
+		} else {
+			prevSeq++
+		}
+
+		// Events written before chaining lack fields added since, so they are only decoded
+		if hash == "" {
+			if chained {
+				return fail(record.Seq, "missing hash")
+			}
+			report.Unhashed++
+			continue
+		}
+		chained = true
+
+		// The hash covers the fields the event type knows; anything else was added by hand
+		if fields, err := unknownFields(event, wrapper.Data); err != nil {
+			return fail(record.Seq, "%v", err)
+		} else if len(fields) > 0 {
+			return fail(record.Seq, "unknown fields %s (fields were added)", strings.Join(fields, ", "))
+		}
+
+		if prev != prevHash {
+			return fail(record.Seq, "previous hash does not match (events were removed, inserted or reordered)")
+		}
+		expected, err := EventHash(event)
+		if err != nil {
+			return nil, err
+		}
+		if hash != expected {
+			return fail(record.Seq, "contents do not match the hash (the event was modified)")
+		}
+		prevHash = hash
+	}
+
+	// Removing events from the end leaves a valid chain - only the head reveals it
+	atEnd := func(reason string) *LogProblem {
+		if len(wrappers) == 0 {
+			return &LogProblem{Reason: reason}
+		}
+		return &LogProblem{Index: len(wrappers) - 1, Seq: prevSeq, Type: wrappers[len(wrappers)-1].Type, Reason: reason}
+	}
+	switch head := report.Head; {
+	case head == nil && chained:
+		report.Problem = atEnd("events.head is missing, so removed events cannot be ruled out (hearth writes it with every event)")
+	case head == nil:
+	case head.Seq > prevSeq:
+		report.Problem = atEnd(fmt.Sprintf("log ends here but the head records #%d (events were removed from the end)", head.Seq))
+	case head.Seq != prevSeq || head.Hash != prevHash:
+		report.Problem = atEnd("does not match the recorded head (events were appended or changed without hearth)")
+	}
+
+	return report, nil
+}
+
+// decodeEvent decodes one stored event, failing on unknown types
+func decodeEvent(engine *atmos.Engine, eventType string, data json.RawMessage) (atmos.Event, error) {
+	wrapped, err := json.Marshal([]map[string]any{{"Type": eventType, "Data": data}})
+	if err != nil {
+		return nil, err
+	}
+	events, err := engine.UnmarshalEvents(wrapped)
+	if err != nil || len(events) == 0 {
+		return nil, fmt.Errorf("cannot decode event of type %q", eventType)
+	}
+	return events[0], nil
+}
+
+// unknownFields returns the stored fields the decoded event has no place for, sorted
+func unknownFields(event atmos.Event, data json.RawMessage) ([]string, error) {
+	var stored map[string]json.RawMessage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("cannot decode event of type %q: %w", event.Type(), err)
+	}
+	decoded, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(decoded, &known); err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for _, field := range sortedKeys(stored) {
+		if _, ok := known[field]; !ok {
+			unknown = append(unknown, field)
+		}
+	}
+	return unknown, nil
+}
@@ -0,0 +1,167 @@
+package hearth_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVerifyLog_IntactAfterRun tests that a log written by a full run verifies cleanly
+func TestVerifyLog_IntactAfterRun(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Build feature", Subtasks: []hearthtest.Subtask{{Title: "Design"}, {Title: "Implement"}}},
+	})
+	ws.AddTask("Build feature", "", "")
+	ws.Run()
+
+	report, err := hearth.VerifyLog(ws.Dir)
+	assert.NoError(t, err)
+	assert.Nil(t, report.Problem)
+	assert.Equal(t, len(ws.Hearth.Engine().GetEvents()), report.Events)
+	assert.Zero(t, report.Unhashed)
+	if assert.NotNil(t, report.Head) {
+		assert.Equal(t, int64(report.Events), report.Head.Seq)
+	}
+}
+
+// TestVerifyLog_DetectsTampering tests that edits, reordering and truncation are reported
+// with the first bad event
+func TestVerifyLog_DetectsTampering(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tamper     func(entries []map[string]any) []map[string]any
+		removeHead bool
+		reason     string
+		seq        int64
+	}{
+		"modified": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				entries[1]["data"].(map[string]any)["Title"] = "Something else"
+				return entries
+			},
+			reason: "contents do not match the hash",
+			seq:    2,
+		},
+		"field added": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				entries[0]["data"].(map[string]any)["Injected"] = true
+				return entries
+			},
+			reason: "unknown fields Injected",
+			seq:    1,
+		},
+		"reordered": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				entries[1], entries[2] = entries[2], entries[1]
+				return entries
+			},
+			reason: "expected event #2",
+			seq:    3,
+		},
+		"removed": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				return append(entries[:1], entries[2:]...)
+			},
+			reason: "expected event #2",
+			seq:    3,
+		},
+		"truncated": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				return entries[:2]
+			},
+			reason: "the head records #3",
+			seq:    2,
+		},
+		"truncated without head": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				return entries[:2]
+			},
+			removeHead: true,
+			reason:     "events.head is missing",
+			seq:        2,
+		},
+		"unknown type": {
+			tamper: func(entries []map[string]any) []map[string]any {
+				entries[2]["type"] = "task_teleported"
+				return entries
+			},
+			reason: `cannot decode event of type "task_teleported"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ws := hearthtest.NewWorkspace(t, nil)
+			for _, title := range []string{"One", "Two", "Three"} {
+				ws.AddTask(title, "", "")
+			}
+
+			path := filepath.Join(ws.Dir, ".hearth", "events.json")
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			var entries []map[string]any
+			assert.NoError(t, json.Unmarshal(data, &entries))
+			data, err = json.Marshal(tc.tamper(entries))
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(path, data, 0644))
+			if tc.removeHead {
+				assert.NoError(t, os.Remove(hearth.LogHeadPath(ws.Dir)))
+			}
+
+			report, err := hearth.VerifyLog(ws.Dir)
+			assert.NoError(t, err)
+			if assert.NotNil(t, report.Problem) {
+				assert.Contains(t, report.Problem.Reason, tc.reason)
+				assert.Equal(t, tc.seq, report.Problem.Seq)
+				assert.NotContains(t, report.Problem.String(), "()")
+			}
+		})
+	}
+}
+
+// TestVerifyLog_AcceptsLegacyPrefix tests that a log written before hashing (and before most
+// event fields existed) verifies, and keeps verifying once hashed events are appended
+func TestVerifyLog_AcceptsLegacyPrefix(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".hearth"), 0755))
+	legacy, err := os.ReadFile(filepath.Join("testdata", "legacy_events.json"))
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".hearth", "events.json"), legacy, 0644))
+
+	report, err := hearth.VerifyLog(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, report.Problem)
+	assert.Equal(t, 2, report.Unhashed)
+	assert.Nil(t, report.Head)
+
+	h, err := hearth.New(hearth.WithWorkspace(dir), hearth.WithCaller(hearthtest.NewAgent(dir)))
+	assert.NoError(t, err)
+	assert.Len(t, h.GetTasks(), 2)
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: "T-2", Title: "New", Time: h.Now()}))
+
+	report, err = hearth.VerifyLog(dir)
+	assert.NoError(t, err)
+	assert.Nil(t, report.Problem)
+	assert.Equal(t, 2, report.Unhashed)
+	assert.Equal(t, 3, report.Events)
+
+	// Stripping the hashes to pass an edit off as legacy is caught by the head
+	path := filepath.Join(dir, ".hearth", "events.json")
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var entries []map[string]any
+	assert.NoError(t, json.Unmarshal(data, &entries))
+	delete(entries[2]["data"].(map[string]any), "Hash")
+	entries[2]["data"].(map[string]any)["Title"] = "Edited"
+	data, err = json.Marshal(entries)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+
+	report, err = hearth.VerifyLog(dir)
+	assert.NoError(t, err)
+	if assert.NotNil(t, report.Problem) {
+		assert.Contains(t, report.Problem.Reason, "does not match the recorded head")
+	}
+}
@@ -0,0 +1,200 @@
+package hearth
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// File change kinds recorded on TaskExecuted
+const (
+	FileAdded    = "added"
+	FileModified = "modified"
+	FileDeleted  = "deleted"
+)
+
+// FileChange is one file an agent created, modified or deleted
+type FileChange struct {
+	Path   string // relative to the agent's working directory
+	Status string // added, modified or deleted
+}
+
+func (c FileChange) String() string {
+	return fmt.Sprintf("%s (%s)", c.Path, c.Status)
+}
+
+// FileSnapshot captures the state of a working directory so changes can be listed later
+// Inside git the whole work tree (tracked and untracked, minus ignored files) is written
+// as a tree object; elsewhere file sizes and modification times are recorded
+type FileSnapshot struct {
+	dir   string
+	tree  string            // git tree of the work tree ("" outside git)
+	files map[string]string // path → size and mtime (outside git)
+}
+
+// TakeSnapshot records the current state of dir (excluding .hearth)
+func TakeSnapshot(dir string) (*FileSnapshot, error) {
+	if IsGitRepo(dir) {
+		tree, err := gitWorkTree(dir)
+		if err != nil {
+			return nil, err
+		}
+		return &FileSnapshot{dir: dir, tree: tree}, nil
+	}
+
+	files, err := scanFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSnapshot{dir: dir, files: files}, nil
+}
+
+// Changes lists the files that changed since the snapshot, sorted by path
+func (s *FileSnapshot) Changes() ([]FileChange, error) {
+	if s.tree != "" {
+		after, err := gitWorkTree(s.dir)
+		if err != nil {
+			return nil, err
+		}
+		return gitTreeChanges(s.dir, s.tree, after)
+	}
+
+	after, err := scanFiles(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	for path, fingerprint := range after {
+		before, existed := s.files[path]
+		switch {
+		case !existed:
+			changes = append(changes, FileChange{Path: path, Status: FileAdded})
+		case before != fingerprint:
+			changes = append(changes, FileChange{Path: path, Status: FileModified})
+		}
+	}
+	for path := range s.files {
+		if _, exists := after[path]; !exists {
+			changes = append(changes, FileChange{Path: path, Status: FileDeleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// gitWorkTree writes the current work tree to a git tree object using a scratch index,
+// leaving the repository's real index untouched
+func gitWorkTree(dir string) (string, error) {
+	scratchDir, err := os.MkdirTemp("", "hearth-index-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch index: %w", err)
+	}
+	defer os.RemoveAll(scratchDir)
+	scratch := filepath.Join(scratchDir, "index")
+
+	// Start from the real index so unchanged files are not re-hashed
+	if index, err := runGit(dir, "rev-parse", "--path-format=absolute", "--git-path", "index"); err == nil {
+		if data, err := os.ReadFile(index); err == nil {
+			if err := os.WriteFile(scratch, data, 0644); err != nil {
+				return "", fmt.Errorf("failed to create scratch index: %w", err)
+			}
+		}
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + scratch}
+	if _, err := runGitEnv(dir, env, "add", "-A", "--", ".", hearthPathspec); err != nil {
+		return "", fmt.Errorf("failed to snapshot work tree: %w", err)
+	}
+	return runGitEnv(dir, env, "write-tree")
+}
+
+// gitTreeChanges lists the files under dir that differ between two trees
+func gitTreeChanges(dir, before, after string) ([]FileChange, error) {
+	if before == after {
+		return nil, nil
+	}
+	out, err := runGit(dir, "diff-tree", "-r", "-z", "--no-renames", "--relative", "--name-status", before, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes: %w", err)
+	}
+
+	// -z output alternates status and path, each NUL-terminated
+	var changes []FileChange
+	fields := strings.Split(strings.TrimRight(out, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		path := fields[i+1]
+		status := FileModified
+		switch fields[i] {
+		case "A":
+			status = FileAdded
+		case "D":
+			status = FileDeleted
+		}
+		changes = append(changes, FileChange{Path: path, Status: status})
+	}
+	return changes, nil
+}
+
+// scanFiles fingerprints every file under dir, skipping .hearth and .git
+func scanFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name := entry.Name(); path != dir && (name == ".hearth" || name == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan files: %w", err)
+	}
+	return files, nil
+}
+
+// mergeFileChanges folds a new execution's changes into a task's accumulated list
+// A file added earlier stays added when modified again, and disappears when deleted again
+func mergeFileChanges(existing, changes []FileChange) []FileChange {
+	byPath := make(map[string]string, len(existing))
+	for _, change := range existing {
+		byPath[change.Path] = change.Status
+	}
+	for _, change := range changes {
+		previous, seen := byPath[change.Path]
+		switch {
+		case !seen:
+			byPath[change.Path] = change.Status
+		case previous == FileAdded && change.Status == FileDeleted:
+			delete(byPath, change.Path)
+		case previous == FileAdded:
+			// still a new file
+		case previous == FileDeleted && change.Status == FileAdded:
+			byPath[change.Path] = FileModified
+		default:
+			byPath[change.Path] = change.Status
+		}
+	}
+
+	merged := make([]FileChange, 0, len(byPath))
+	for _, path := range sortedKeys(byPath) {
+		merged = append(merged, FileChange{Path: path, Status: byPath[path]})
+	}
+	return merged
+}
@@ -0,0 +1,83 @@
+package hearth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFileChanges_RecordedAndSharedWithSiblings tests that executions record the files
+// they touched and later siblings see them in their context
+func TestFileChanges_RecordedAndSharedWithSiblings(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Feature", Subtasks: []hearthtest.Subtask{{Title: "Write"}, {Title: "Next"}}},
+		{Title: "Write", Files: map[string]string{"pkg/a.go": "package pkg"}},
+	})
+	ws.AddTask("Feature", "", "")
+
+	ws.Run()
+
+	assert.Equal(t, []hearth.FileChange{{Path: "pkg/a.go", Status: hearth.FileAdded}}, ws.Hearth.GetTask("T-1.1").Files)
+	assert.Empty(t, ws.Hearth.GetTask("T-1.2").Files)
+
+	calls := ws.Agent.Calls()
+	assert.Equal(t, "Next", calls[2].Title)
+	assert.Contains(t, calls[2].Prompt, "Files changed: pkg/a.go (added)")
+}
+
+// TestFileSnapshot_Git tests change detection in a git work tree, ignoring files
+// that were already dirty and unchanged, and leaving the index alone
+func TestFileSnapshot_Git(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	git(t, dir, "init", "-q")
+	write("keep.txt", "keep")
+	write("edit.txt", "old")
+	write("gone.txt", "gone")
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "-q", "-m", "initial")
+	write("dirty.txt", "already untracked")
+
+	snapshot, err := hearth.TakeSnapshot(dir)
+	assert.NoError(t, err)
+
+	write("edit.txt", "new")
+	write("new.txt", "new")
+	assert.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".hearth"), 0755))
+	write(".hearth/events.json", "[]")
+
+	changes, err := snapshot.Changes()
+	assert.NoError(t, err)
+	assert.Equal(t, []hearth.FileChange{
+		{Path: "edit.txt", Status: hearth.FileModified},
+		{Path: "gone.txt", Status: hearth.FileDeleted},
+		{Path: "new.txt", Status: hearth.FileAdded},
+	}, changes)
+	assert.Empty(t, git(t, dir, "diff", "--cached", "--name-only"))
+}
+
+// TestFileSnapshot_NoGit tests change detection by scanning outside a repository
+func TestFileSnapshot_NoGit(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "gone.txt"), []byte("x"), 0644))
+
+	snapshot, err := hearth.TakeSnapshot(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Remove(filepath.Join(dir, "gone.txt")))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("y"), 0644))
+
+	changes, err := snapshot.Changes()
+	assert.NoError(t, err)
+	assert.Equal(t, []hearth.FileChange{
+		{Path: "gone.txt", Status: hearth.FileDeleted},
+		{Path: "new.txt", Status: hearth.FileAdded},
+	}, changes)
+}
@@ -0,0 +1,13 @@
+package hearth
+
+import "time"
+
+// Clock provides the current time (allows deterministic timestamps in tests)
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
@@ -2,8 +2,9 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
-	"github.com/google/uuid"
+	"github.com/fmizzell/hearth"
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +12,11 @@ var (
 	addTitle       string
 	addDescription string
 	addParent      string
+	addModel       string
+	addAgent       string
+	addVerify      string
+	addReview      bool
+	addScope       []string
 )
 
 var addCmd = &cobra.Command{
@@ -24,6 +30,11 @@ func init() {
 	addCmd.Flags().StringVarP(&addTitle, "title", "t", "", "Task title (required)")
 	addCmd.Flags().StringVarP(&addDescription, "description", "d", "", "Task description")
 	addCmd.Flags().StringVarP(&addParent, "parent", "p", "", "Parent task ID (for hierarchical tasks)")
+	addCmd.Flags().StringVar(&addVerify, "verify", "", "Command that must pass before the task completes, e.g. \"go test ./...\" (default: inherited)")
+	addCmd.Flags().BoolVar(&addReview, "review", false, "Review this task and its subtasks before completion; --review=false opts out (default: inherited)")
+	addCmd.Flags().StringArrayVar(&addScope, "scope", nil, "Path glob this task and its subtasks may touch, e.g. 'internal/auth/**' (repeatable, default: inherited)")
+	addCmd.Flags().StringVar(&addModel, "model", "", "Model for this task and its subtasks (default: inherited)")
+	addCmd.Flags().StringVar(&addAgent, "agent", "", "Agent backend for this task and its subtasks (default: inherited)")
 	if err := addCmd.MarkFlagRequired("title"); err != nil {
 		panic(fmt.Sprintf("Failed to mark title flag as required: %v", err))
 	}
@@ -35,17 +46,29 @@ func addTask(cmd *cobra.Command, args []string) {
 		fatal("Failed to get workspace directory: %v", err)
 	}
 
-	// Generate task ID
-	taskID := generateTaskID()
-
 	// Prepare optional parent pointer
 	var parentPtr *string
 	if addParent != "" {
 		parentPtr = &addParent
 	}
 
+	// Only record a review choice when the flag was given, so the default is inherited
+	var reviewPtr *bool
+	if cmd.Flags().Changed("review") {
+		reviewPtr = &addReview
+	}
+
 	// Create task using helper (loads, creates, saves)
-	err = createTask(workspaceDir, taskID, addTitle, addDescription, parentPtr)
+	taskID, err := createTask(workspaceDir, &hearth.TaskCreated{
+		Title:       addTitle,
+		Description: addDescription,
+		ParentID:    parentPtr,
+		Verify:      addVerify,
+		Review:      reviewPtr,
+		Agent:       addAgent,
+		Model:       addModel,
+		Scope:       addScope,
+	})
 	if err != nil {
 		fatal("%v", err)
 	}
@@ -59,9 +82,19 @@ func addTask(cmd *cobra.Command, args []string) {
 	if addParent != "" {
 		fmt.Printf("  Parent: %s\n", addParent)
 	}
-}
-
-func generateTaskID() string {
-	// Generate short UUID-based ID
-	return "T-" + uuid.New().String()[:8]
+	if addVerify != "" {
+		fmt.Printf("  Verify: %s\n", addVerify)
+	}
+	if reviewPtr != nil {
+		fmt.Printf("  Review: %t\n", addReview)
+	}
+	if len(addScope) > 0 {
+		fmt.Printf("  Scope: %s\n", strings.Join(addScope, ", "))
+	}
+	if addAgent != "" {
+		fmt.Printf("  Agent: %s\n", addAgent)
+	}
+	if addModel != "" {
+		fmt.Printf("  Model: %s\n", addModel)
+	}
 }
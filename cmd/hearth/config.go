@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read, change and validate workspace configuration",
+	Long: `Read, change and validate the workspace configuration in .hearth/config.yaml.
+
+Values are resolved from defaults, the config file, HEARTH_* environment
+variables (e.g. HEARTH_RETRY_MAX_ATTEMPTS) and --set flags, in that order.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show effective config values (all keys if none given)",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   configGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in .hearth/config.yaml",
+	Args:  cobra.ExactArgs(2),
+	Run:   configSet,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the effective config for errors",
+	Args:  cobra.NoArgs,
+	Run:   configValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+func configGet(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	cfg, err := loadConfig(workspaceDir)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if len(args) == 1 {
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Println(value)
+		return
+	}
+
+	for _, key := range hearth.ConfigKeys() {
+		value, _ := cfg.Get(key)
+		fmt.Printf("%s = %s\n", key, value)
+	}
+}
+
+func configSet(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	// Only the file is rewritten - env and --set overrides are not persisted
+	cfg, err := hearth.ReadConfigFile(workspaceDir)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if err := cfg.Set(args[0], args[1]); err != nil {
+		fatal("%v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal("%v", err)
+	}
+	if err := cfg.Save(workspaceDir); err != nil {
+		fatal("%v", err)
+	}
+
+	fmt.Printf("✓ %s = %s\n", args[0], args[1])
+}
+
+func configValidate(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	cfg, err := loadConfig(workspaceDir)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal("%v", err)
+	}
+
+	fmt.Println("✓ Config is valid")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFix        bool
+	doctorStuckAfter time.Duration
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the workspace for problems and repair them",
+	Long: `Check that the agent CLI and hearth are on PATH, that the event log parses and is
+intact, and that the task tree is healthy: no orphaned tasks (whose parent does not exist),
+no missing or stale result files, no tasks left in progress by an interrupted run, and no
+parents whose subtasks all completed without the parent being summarized.
+
+In-progress work only counts as abandoned once nothing has been recorded for --stuck-after
+(default: the agent timeout), so a run in another terminal is left alone.
+
+--fix emits the corrective events: orphaned tasks are deleted with their subtasks, stuck
+tasks are reopened as todo, and missing summaries are written by the agent to complete
+their parents. Nothing else runs; use hearth run to continue. Other problems are reported
+with a hint.`,
+	Args: cobra.NoArgs,
+	Run:  doctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Emit the corrective events for the problems found")
+	doctorCmd.Flags().DurationVar(&doctorStuckAfter, "stuck-after", 0, "How long the log must be quiet before in-progress work counts as abandoned (default: the agent timeout)")
+}
+
+func doctor(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	// Repairs are tagged like a run, since summaries call the agent
+	if doctorFix {
+		os.Setenv(hearth.RunIDEnv, hearth.NewRunID())
+	}
+
+	h, err := openHearth(workspaceDir)
+	if err != nil {
+		fatal("Failed to load hearth: %v", err)
+	}
+
+	environment := hearth.DiagnoseEnvironment(h.Config())
+	eventLog := hearth.DiagnoseEventLog(workspaceDir)
+	tasks := h.DiagnoseTasks(doctorStuckAfter)
+
+	printChecks("Environment", environment)
+	printChecks("Event log", eventLog)
+	printChecks("Tasks", tasks)
+
+	fixes := 0
+	for _, diagnosis := range tasks {
+		fixes += len(diagnosis.Fixes)
+	}
+	problems := countProblems(environment) + countProblems(eventLog) + countProblems(tasks)
+	fmt.Println()
+
+	switch {
+	case problems == 0:
+		fmt.Println("✓ No problems found")
+	case !doctorFix:
+		fmt.Printf("%d problem(s) found", problems)
+		if fixes > 0 {
+			fmt.Printf("; run hearth doctor --fix to apply %d fix(es)", fixes)
+		}
+		fmt.Println()
+		os.Exit(1)
+	case len(eventLog) > 0 && fixes > 0:
+		// Appending rewrites the log, which would drop the events that no longer decode
+		fatal("Not applying fixes: repair the event log first")
+	case fixes == 0:
+		fmt.Printf("%d problem(s) found; none can be fixed automatically\n", problems)
+		os.Exit(1)
+	default:
+		emitted, err := h.Repair(tasks)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Printf("✓ Applied %d fix(es)\n", emitted)
+		if remaining := problems - countFixable(tasks); remaining > 0 {
+			fmt.Printf("%d problem(s) need fixing by hand\n", remaining)
+			os.Exit(1)
+		}
+	}
+}
+
+// printChecks prints one group of diagnoses
+func printChecks(title string, diagnoses []hearth.Diagnosis) {
+	switch {
+	case countProblems(diagnoses) > 0:
+		fmt.Printf("✗ %s\n", title)
+	case len(diagnoses) > 0:
+		fmt.Printf("⚠ %s\n", title)
+	default:
+		fmt.Printf("✓ %s\n", title)
+		return
+	}
+	for _, diagnosis := range diagnoses {
+		subject := diagnosis.Check
+		if diagnosis.TaskID != "" {
+			subject += " " + diagnosis.TaskID
+		}
+		bullet := "•"
+		if diagnosis.Warning {
+			bullet = "⚠"
+		}
+		fmt.Printf("  %s %s: %s\n", bullet, subject, diagnosis.Problem)
+		for _, event := range diagnosis.Fixes {
+			fmt.Printf("      fix: %s\n", describeFix(event))
+		}
+		if len(diagnosis.Fixes) == 0 && diagnosis.Hint != "" {
+			fmt.Printf("      hint: %s\n", diagnosis.Hint)
+		}
+	}
+}
+
+// describeFix explains what a corrective event does
+func describeFix(event atmos.Event) string {
+	switch e := event.(type) {
+	case *hearth.TaskDeleted:
+		return "delete " + e.TaskID
+	case *hearth.TaskReopened:
+		return fmt.Sprintf("reopen %s as %s", e.TaskID, e.Status)
+	case *hearth.SummaryRequested:
+		return fmt.Sprintf("summarize and complete %s (calls the agent)", e.ParentTaskID)
+	}
+	return event.Type()
+}
+
+// countProblems counts the diagnoses that are not warnings
+func countProblems(diagnoses []hearth.Diagnosis) int {
+	count := 0
+	for _, diagnosis := range diagnoses {
+		if !diagnosis.Warning {
+			count++
+		}
+	}
+	return count
+}
+
+// countFixable counts the diagnoses that come with corrective events
+func countFixable(diagnoses []hearth.Diagnosis) int {
+	count := 0
+	for _, diagnosis := range diagnoses {
+		if len(diagnosis.Fixes) > 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	eventsTask     string
+	eventsTypes    []string
+	eventsSince    string
+	eventsUntil    string
+	eventsRun      string
+	eventsJSON     bool
+	eventsFollow   bool
+	eventsInterval time.Duration
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	Aliases: []string{"log"},
+	Short:   "List and follow the event log",
+	Long: `List the events in the workspace's event log, oldest first.
+Filter by task, event type, time range or run. --since and --until take a timestamp
+(RFC 3339 or YYYY-MM-DD) or a duration back from now (e.g. 30m).
+With --follow, keep printing new events as other processes (e.g. a running
+"hearth run") append them. --json prints one JSON object per line.`,
+	Args: cobra.NoArgs,
+	Run:  listEvents,
+}
+
+func init() {
+	eventsCmd.Flags().StringVarP(&eventsTask, "task", "t", "", "Only events concerning this task")
+	eventsCmd.Flags().StringSliceVar(&eventsTypes, "type", nil, "Only events of these types (e.g. task_executed, repeatable or comma-separated)")
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Only events at or after this time or duration ago")
+	eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "Only events before this time or duration ago")
+	eventsCmd.Flags().StringVar(&eventsRun, "run", "", "Only events emitted during this run (see the run ID printed by hearth run)")
+	eventsCmd.Flags().BoolVar(&eventsJSON, "json", false, "Print events as JSON lines")
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "Keep printing new events as they are appended")
+	eventsCmd.Flags().DurationVar(&eventsInterval, "interval", 500*time.Millisecond, "How often --follow checks for new events")
+}
+
+func listEvents(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	h, err := openHearth(workspaceDir)
+	if err != nil {
+		fatal("Failed to load hearth: %v", err)
+	}
+
+	filter := hearth.EventFilter{TaskID: eventsTask, Types: eventsTypes, RunID: eventsRun}
+	if filter.Since, err = parseTimeFlag(eventsSince, h.Now()); err != nil {
+		fatal("Invalid --since: %v", err)
+	}
+	if filter.Until, err = parseTimeFlag(eventsUntil, h.Now()); err != nil {
+		fatal("Invalid --until: %v", err)
+	}
+
+	// Filter ourselves so following can track the newest event even when it doesn't match
+	// (the file repository re-reads the log on every query, so polling sees other processes' events)
+	var after int64
+	for first := true; ; first = false {
+		var records []hearth.EventRecord
+		for _, record := range h.Events(hearth.EventFilter{AfterSeq: after}) {
+			if filter.Matches(record.Event) {
+				records = append(records, record)
+			}
+			after = record.Seq
+		}
+
+		if first && len(records) == 0 && !eventsFollow && !eventsJSON {
+			fmt.Println("No events found.")
+		}
+		printEvents(records)
+
+		if !eventsFollow {
+			return
+		}
+		time.Sleep(eventsInterval)
+	}
+}
+
+// printEvents prints events as JSON lines or one line each for humans
+func printEvents(records []hearth.EventRecord) {
+	for _, record := range records {
+		if eventsJSON {
+			data, err := json.Marshal(record)
+			if err != nil {
+				fatal("Failed to encode event: %v", err)
+			}
+			fmt.Println(string(data))
+			continue
+		}
+
+		line := fmt.Sprintf("#%-5d %s  %-23s", record.Seq, formatTime(record.Time), record.Type)
+		if record.TaskID != "" {
+			line += " " + record.TaskID
+		}
+		if description := describeEvent(record.Event); description != "" {
+			line += "  " + description
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
+
+// parseTimeFlag parses an absolute time (RFC 3339 or YYYY-MM-DD, local time) or a duration before now
+func parseTimeFlag(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a timestamp or duration", value)
+}
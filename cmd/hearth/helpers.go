@@ -2,33 +2,40 @@ package main
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/fmizzell/hearth"
 )
 
 // createTask creates a task and saves it to disk
-func createTask(workspaceDir, taskID, title, description string, parentID *string) error {
+// The task ID is generated with the workspace's configured ID strategy;
+// the event's TaskID and Time are filled in
+func createTask(workspaceDir string, event *hearth.TaskCreated) (string, error) {
 	// Load hearth with persistence
-	h, err := hearth.NewHearth(workspaceDir)
+	h, err := openHearth(workspaceDir)
 	if err != nil {
-		return fmt.Errorf("failed to load hearth: %w", err)
+		return "", fmt.Errorf("failed to load hearth: %w", err)
 	}
 
-	// Create task event
-	event := &hearth.TaskCreated{
-		TaskID:      taskID,
-		Title:       title,
-		Description: description,
-		ParentID:    parentID,
-		Time:        time.Now(),
+	// Reject agents the workspace doesn't define
+	if event.Agent != "" {
+		if _, err := hearth.NewAgentCaller(h.Config(), event.Agent, event.Model); err != nil {
+			return "", err
+		}
 	}
 
+	if err := hearth.ValidateScope(event.Scope); err != nil {
+		return "", err
+	}
+
+	// Generate task ID (checked against existing tasks)
+	event.TaskID = h.NextTaskID(event.ParentID, event.Title, event.Description)
+	event.Time = h.Now()
+
 	// Process event (auto-persists via FileRepository)
 	err = h.Process(event)
 	if err != nil {
-		return fmt.Errorf("failed to create task: %w", err)
+		return "", fmt.Errorf("failed to create task %s: %w", event.TaskID, err)
 	}
 
-	return nil
+	return event.TaskID, nil
 }
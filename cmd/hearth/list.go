@@ -11,17 +11,21 @@ import (
 
 var (
 	statusFilter string
+	listAt       string
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tasks",
-	Long:  `List all tasks in the current workspace with their status.`,
-	Run:   listTasks,
+	Long: `List all tasks in the current workspace with their status.
+With --at, list the tasks as they were at an earlier point: after an event
+(sequence number, see hearth events) or at a time.`,
+	Run: listTasks,
 }
 
 func init() {
-	listCmd.Flags().StringVarP(&statusFilter, "status", "s", "", "Filter tasks by status (todo, in-progress, completed)")
+	listCmd.Flags().StringVarP(&statusFilter, "status", "s", "", "Filter tasks by status (todo, in-progress, completed, failed)")
+	listCmd.Flags().StringVar(&listAt, "at", "", "Show the tasks as they were after event N (#N) or at a time (RFC 3339 or YYYY-MM-DD)")
 }
 
 func listTasks(cmd *cobra.Command, args []string) {
@@ -31,12 +35,21 @@ func listTasks(cmd *cobra.Command, args []string) {
 	}
 
 	// Load hearth with persistence
-	h, err := hearth.NewHearth(workspaceDir)
+	h, err := openHearth(workspaceDir)
 	if err != nil {
 		fatal("Failed to load hearth: %v", err)
 	}
 
-	tasks := h.GetTasks()
+	at, err := parseAt(listAt)
+	if err != nil {
+		fatal("%v", err)
+	}
+	state, err := h.StateAt(at)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	tasks := state.Tasks
 	if len(tasks) == 0 {
 		fmt.Println("No tasks found.")
 		return
@@ -62,7 +75,11 @@ func listTasks(cmd *cobra.Command, args []string) {
 	orderedTasks := getTasksInDepthFirstOrder(filteredTasks)
 
 	// Display task tree
-	fmt.Println("📋 Tasks:")
+	if at.IsPresent() {
+		fmt.Println("📋 Tasks:")
+	} else {
+		fmt.Printf("📋 Tasks at %s:\n", at)
+	}
 	fmt.Println()
 
 	// Display each task in depth-first order with proper indentation
@@ -80,7 +97,7 @@ func getTasksInDepthFirstOrder(tasks map[string]*hearth.Task) []*hearth.Task {
 		taskSlice = append(taskSlice, task)
 	}
 
-	// Find roots and sort by creation time
+	// Find roots and sort by creation order
 	var roots []*hearth.Task
 	for _, task := range taskSlice {
 		if task.ParentID == nil {
@@ -88,7 +105,7 @@ func getTasksInDepthFirstOrder(tasks map[string]*hearth.Task) []*hearth.Task {
 		}
 	}
 	sort.Slice(roots, func(i, j int) bool {
-		return roots[i].CreatedAt.Before(roots[j].CreatedAt)
+		return roots[i].CreatedBefore(roots[j])
 	})
 
 	// Collect all tasks in depth-first order
@@ -105,7 +122,7 @@ func getTasksInDepthFirstOrder(tasks map[string]*hearth.Task) []*hearth.Task {
 func collectDepthFirst(task *hearth.Task, taskMap map[string]*hearth.Task, result *[]*hearth.Task) {
 	*result = append(*result, task)
 
-	// Get children and sort by creation time
+	// Get children and sort by creation order
 	var children []*hearth.Task
 	for _, t := range taskMap {
 		if t.ParentID != nil && *t.ParentID == task.ID {
@@ -113,7 +130,7 @@ func collectDepthFirst(task *hearth.Task, taskMap map[string]*hearth.Task, resul
 		}
 	}
 	sort.Slice(children, func(i, j int) bool {
-		return children[i].CreatedAt.Before(children[j].CreatedAt)
+		return children[i].CreatedBefore(children[j])
 	})
 
 	// Recursively collect children
@@ -140,19 +157,21 @@ func calculateIndent(task *hearth.Task, taskMap map[string]*hearth.Task) int {
 // displayTaskLine displays a single task line with proper formatting
 func displayTaskLine(task *hearth.Task, indent int) {
 	prefix := strings.Repeat("  ", indent)
+	fmt.Printf("%s%s [%s] %s\n", prefix, statusIcon(task.Status), task.ID, task.Title)
+}
 
-	// Status icon
-	var statusIcon string
-	switch task.Status {
+// statusIcon returns the icon shown for a task status
+func statusIcon(status string) string {
+	switch status {
 	case "completed":
-		statusIcon = "✓"
+		return "✓"
 	case "in-progress":
-		statusIcon = "→"
+		return "→"
+	case "failed":
+		return "✗"
 	default: // "todo"
-		statusIcon = "○"
+		return "○"
 	}
-
-	fmt.Printf("%s%s [%s] %s\n", prefix, statusIcon, task.ID, task.Title)
 }
 
 // matchesStatus checks if a task status matches the filter
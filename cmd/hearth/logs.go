@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsAttempt int
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <task-id>",
+	Short: "Show the execution log of a task",
+	Long:  `Show the execution log (argv, env, timings, exit code, stdout and stderr) of a task's latest attempt, or of a specific attempt with --attempt.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   showLogs,
+}
+
+func init() {
+	logsCmd.Flags().IntVarP(&logsAttempt, "attempt", "a", 0, "Attempt number (defaults to the latest)")
+}
+
+func showLogs(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	taskID := args[0]
+	attempts, err := hearth.ExecutionAttempts(workspaceDir, taskID)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if len(attempts) == 0 {
+		fmt.Printf("No execution logs for task %s\n", taskID)
+		return
+	}
+
+	attempt := logsAttempt
+	if attempt == 0 {
+		attempt = attempts[len(attempts)-1]
+	}
+
+	data, err := os.ReadFile(hearth.ExecutionLogPath(workspaceDir, taskID, attempt))
+	if os.IsNotExist(err) {
+		fatal("No log for attempt %d of task %s (available: %v)", attempt, taskID, attempts)
+	}
+	if err != nil {
+		fatal("Failed to read log: %v", err)
+	}
+
+	fmt.Print(string(data))
+}
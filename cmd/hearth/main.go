@@ -3,14 +3,20 @@ package main
 import (
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/fmizzell/hearth"
 	"github.com/spf13/cobra"
 )
 
 var (
 	workspaceFlag string
+	configSets    []string
+	logFormatFlag string
+	logLevelFlag  string
 )
 
 var rootCmd = &cobra.Command{
@@ -29,10 +35,22 @@ func main() {
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&workspaceFlag, "workspace", "w", "", "Workspace directory (defaults to current directory)")
+	rootCmd.PersistentFlags().StringArrayVar(&configSets, "set", nil, "Override a config value for this command (key=value, repeatable)")
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", hearth.LogFormatHuman, "Progress output format: human or json")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", "info", "Minimum log level: debug, info, warn or error")
 
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(revertCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(eventsCmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 func getWorkspaceDir() (string, error) {
@@ -56,9 +74,64 @@ func getWorkspaceDir() (string, error) {
 		return "", fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	return absDir, nil
+	// Agents running in an isolated worktree still write to the workspace's log
+	return hearth.WorkspaceRoot(absDir), nil
+}
+
+// loadConfig loads the workspace config and applies --set overrides
+func loadConfig(workspaceDir string) (*hearth.Config, error) {
+	cfg, err := hearth.LoadConfig(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, set := range configSets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q (expected key=value)", set)
+		}
+		if err := cfg.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// newLogger creates the logger selected by --log-format and --log-level
+func newLogger() (*slog.Logger, error) {
+	return hearth.NewLogger(os.Stdout, logFormatFlag, logLevelFlag)
+}
+
+// openHearth loads the workspace with its effective config and logger
+// Extra options are applied last (e.g. a different caller)
+func openHearth(workspaceDir string, opts ...hearth.Option) (*hearth.Hearth, error) {
+	cfg, err := loadConfig(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	logger, err := newLogger()
+	if err != nil {
+		return nil, err
+	}
+
+	return hearth.New(append([]hearth.Option{
+		hearth.WithWorkspace(workspaceDir),
+		hearth.WithConfig(cfg),
+		hearth.WithLogger(logger),
+		hearth.WithRunID(os.Getenv(hearth.RunIDEnv)),
+	}, opts...)...)
 }
 
 func fatal(format string, args ...interface{}) {
 	log.Fatalf(format, args...)
 }
+
+// parseAt parses an --at flag (empty = the present)
+func parseAt(value string) (hearth.HistoryPoint, error) {
+	if value == "" {
+		return hearth.HistoryPoint{}, nil
+	}
+	return hearth.ParseHistoryPoint(value)
+}
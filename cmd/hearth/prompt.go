@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptAttempt int
+	promptNext    bool
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt <task-id>",
+	Short: "Show the prompt sent for a task",
+	Long: `Show the archived prompt of a task's latest execution attempt (or --attempt N).
+With --next, render the prompt that would be sent next without executing anything.`,
+	Args: cobra.ExactArgs(1),
+	Run:  showPrompt,
+}
+
+func init() {
+	promptCmd.Flags().IntVarP(&promptAttempt, "attempt", "a", 0, "Attempt number (defaults to the latest)")
+	promptCmd.Flags().BoolVar(&promptNext, "next", false, "Render the prompt that would be sent next")
+}
+
+func showPrompt(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	taskID := args[0]
+
+	if promptNext {
+		h, err := openHearth(workspaceDir)
+		if err != nil {
+			fatal("Failed to load hearth: %v", err)
+		}
+
+		prompt, err := hearth.BuildNextPrompt(taskID, h.GetTasks(), workspaceDir, h.Config().Context)
+		if err != nil {
+			fatal("%v", err)
+		}
+		fmt.Print(prompt)
+		return
+	}
+
+	attempt := promptAttempt
+	if attempt == 0 {
+		attempts, err := hearth.ExecutionAttempts(workspaceDir, taskID)
+		if err != nil {
+			fatal("%v", err)
+		}
+		if len(attempts) == 0 {
+			fmt.Printf("No archived prompts for task %s (use --next to render one)\n", taskID)
+			return
+		}
+		attempt = attempts[len(attempts)-1]
+	}
+
+	data, err := os.ReadFile(hearth.PromptPath(workspaceDir, taskID, attempt))
+	if os.IsNotExist(err) {
+		fatal("No archived prompt for attempt %d of task %s", attempt, taskID)
+	}
+	if err != nil {
+		fatal("Failed to read prompt: %v", err)
+	}
+
+	fmt.Print(string(data))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var revertCmd = &cobra.Command{
+	Use:   "revert <task-id>",
+	Short: "Revert the checkpoint commits of a task",
+	Long: `Revert the git commits hearth made for a task and its subtasks (see git.checkpoint).
+Each commit is undone with git revert, newest first, in the local repository.`,
+	Args: cobra.ExactArgs(1),
+	Run:  revertTask,
+}
+
+func revertTask(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	h, err := openHearth(workspaceDir)
+	if err != nil {
+		fatal("Failed to load hearth: %v", err)
+	}
+
+	reverted, err := h.RevertTask(args[0])
+	for _, event := range reverted {
+		for i, commit := range event.Commits {
+			fmt.Printf("✓ Reverted %s (%s) with %s\n", hearth.ShortSHA(commit), event.TaskID, hearth.ShortSHA(event.Reverts[i]))
+		}
+	}
+	if err != nil {
+		fatal("%v", err)
+	}
+}
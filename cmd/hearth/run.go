@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
-	"time"
+	"strings"
 
 	"github.com/fmizzell/hearth"
 	"github.com/fmizzell/hearth/prompts"
@@ -11,7 +11,11 @@ import (
 )
 
 var (
-	taskPreset string
+	taskPreset   string
+	runDryRun    bool
+	recordPath   string
+	replayPath   string
+	replayStrict bool
 )
 
 var runCmd = &cobra.Command{
@@ -23,6 +27,10 @@ var runCmd = &cobra.Command{
 
 func init() {
 	runCmd.Flags().StringVar(&taskPreset, "preset", "", "Initialize with a preset task: hello, code-quality")
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Print the execution order and prompts without calling Claude or saving anything")
+	runCmd.Flags().StringVar(&recordPath, "record", "", "Record agent interactions and their side-effect events to a cassette file")
+	runCmd.Flags().StringVar(&replayPath, "replay", "", "Answer agent calls from a cassette file instead of calling Claude")
+	runCmd.Flags().BoolVar(&replayStrict, "replay-strict", true, "Fail the call when a prompt differs from the recording")
 }
 
 func run(cmd *cobra.Command, args []string) {
@@ -37,54 +45,158 @@ func run(cmd *cobra.Command, args []string) {
 		fatal("Workspace directory does not exist: %s", workspaceDir)
 	}
 
-	fmt.Println("🔥 Hearth - Autonomous Task Orchestration")
-	fmt.Printf("📂 Workspace: %s\n", workspaceDir)
-	fmt.Println()
+	logger, err := newLogger()
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	// Tag this run's events, including those recorded by agents calling hearth
+	runID := hearth.NewRunID()
+	if !runDryRun {
+		os.Setenv(hearth.RunIDEnv, runID)
+	}
+
+	logger.Info(hearth.MsgRunStarted, "workspace", workspaceDir, "run_id", runID)
 
 	// Create hearth instance with persistence
 	// Services (workspace dir + Claude caller) are automatically registered
-	h, err := hearth.NewHearth(workspaceDir)
+	callerOpts, err := cassetteCallerOptions(workspaceDir)
+	if err != nil {
+		fatal("%v", err)
+	}
+	h, err := openHearth(workspaceDir, callerOpts...)
 	if err != nil {
 		fatal("Failed to create hearth: %v", err)
 	}
 
+	if runDryRun {
+		dryRun(h, workspaceDir)
+		return
+	}
+
 	// If a preset is specified, create task
 	if taskPreset != "" {
-		var title, description string
-
-		switch taskPreset {
-		case "hello":
-			title = "Hello World Test"
-			description = prompts.Hello
-		case "code-quality":
-			title = "Code Quality Analysis"
-			description = prompts.CodeQualityAnalysis
-		default:
-			fatal("Unknown preset: %s (use 'hello' or 'code-quality')", taskPreset)
-		}
-
-		// Generate unique task ID
-		taskID := fmt.Sprintf("T-%d", time.Now().Unix())
+		title, description := presetTask(taskPreset)
 
-		err = createTask(workspaceDir, taskID, title, description, nil)
+		taskID, err := createTask(workspaceDir, &hearth.TaskCreated{Title: title, Description: description})
 		if err != nil {
 			fatal("Failed to create preset task: %v", err)
 		}
 
-		fmt.Printf("✓ Created task %s from preset '%s'\n", taskID, taskPreset)
-		fmt.Println()
+		logger.Info(hearth.MsgPresetCreated, "task_id", taskID, "preset", taskPreset)
 	}
 
 	// Start autonomous orchestration
-	fmt.Println("🤖 Starting autonomous task execution...")
-	fmt.Println()
+	logger.Info(hearth.MsgExecutionStarted)
 
-	err = h.Process(&hearth.ExecuteTasksRequested{Time: time.Now()})
+	err = h.Process(&hearth.ExecuteTasksRequested{Time: h.Now()})
 	if err != nil {
 		fatal("Failed to start orchestration: %v", err)
 	}
 
+	logger.Info(hearth.MsgRunFinished, "workspace", workspaceDir)
+}
+
+// presetTask returns the title and description of a built-in preset
+func presetTask(preset string) (string, string) {
+	switch preset {
+	case "hello":
+		return "Hello World Test", prompts.Hello
+	case "code-quality":
+		return "Code Quality Analysis", prompts.CodeQualityAnalysis
+	default:
+		fatal("Unknown preset: %s (use 'hello' or 'code-quality')", preset)
+		return "", ""
+	}
+}
+
+// dryRun prints the plan for the current tree (plus any preset task) without persisting anything
+func dryRun(h *hearth.Hearth, workspaceDir string) {
+	// Work on a copy so the preset task never reaches the event log
+	tasks := make(map[string]*hearth.Task)
+	var lastSeq int64
+	for id, task := range h.GetTasks() {
+		tasks[id] = task
+		if task.Seq > lastSeq {
+			lastSeq = task.Seq
+		}
+	}
+
+	if taskPreset != "" {
+		title, description := presetTask(taskPreset)
+		taskID := h.NextTaskID(nil, title, description)
+		tasks[taskID] = &hearth.Task{
+			ID:          taskID,
+			Title:       title,
+			Description: description,
+			Status:      "todo",
+			Seq:         lastSeq + 1,
+			CreatedAt:   h.Now(),
+		}
+	}
+
+	scheduler, err := hearth.NewScheduler(h.Config().Scheduler)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	steps, err := hearth.PlanRun(tasks, scheduler, workspaceDir, h.Config().Context)
+	if err != nil {
+		fatal("Failed to plan run: %v", err)
+	}
+
+	fmt.Println("🧪 Dry run - nothing will be executed or saved")
 	fmt.Println()
-	fmt.Println("✅ All tasks completed!")
-	fmt.Println("🎉 Hearth finished!")
+
+	if len(steps) == 0 {
+		fmt.Println("No eligible tasks.")
+		return
+	}
+
+	fmt.Println("Execution order:")
+	for i, step := range steps {
+		indent := strings.Repeat("  ", step.Depth)
+		fmt.Printf("%3d. %s%-7s [%s] %s\n", i+1, indent, step.Kind, step.Task.ID, step.Task.Title)
+	}
+
+	for i, step := range steps {
+		fmt.Println()
+		fmt.Printf("=== %d. %s %s ===\n", i+1, step.Kind, step.Task.ID)
+		fmt.Print(step.Prompt)
+	}
+}
+
+// cassetteCallerOptions swaps in a replaying caller, or records the configured agents, when requested
+func cassetteCallerOptions(workspaceDir string) ([]hearth.Option, error) {
+	if recordPath == "" && replayPath == "" {
+		return nil, nil
+	}
+	if recordPath != "" && replayPath != "" {
+		return nil, fmt.Errorf("--record and --replay cannot be used together")
+	}
+	cfg, err := loadConfig(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if replayPath != "" {
+		replayer, err := hearth.NewReplayCaller(replayPath, workspaceDir, replayStrict)
+		if err != nil {
+			return nil, err
+		}
+		if replayStrict {
+			if err := replayer.Cassette.CheckReplayable(cfg.IDs.Strategy); err != nil {
+				return nil, err
+			}
+		}
+		return []hearth.Option{hearth.WithCaller(replayer)}, nil
+	}
+
+	// Every backend the session uses (default, per-task, escalation, review) is recorded
+	recorder := &hearth.RecordingCaller{
+		Path:         recordPath,
+		WorkspaceDir: workspaceDir,
+		IDStrategy:   cfg.IDs.Strategy,
+	}
+	return []hearth.Option{hearth.WithCallerWrapper(recorder.Wrap)}, nil
 }
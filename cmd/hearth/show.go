@@ -0,0 +1,307 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	showFiles bool
+	showFull  bool
+	showJSON  bool
+	showAt    string
+)
+
+// previewLines is how much of the result and prompt `hearth show` prints without --full
+const previewLines = 20
+
+var showCmd = &cobra.Command{
+	Use:   "show <task-id>",
+	Short: "Show details of a task",
+	Long: `Show everything recorded about a task: its fields and effective settings, parent chain,
+children, status history, usage, result, last prompt and every event that concerns it.
+The result and prompt are previewed; use --full to print them completely.
+With --files, also list the files the agent created, modified or deleted across all executions.
+With --at, show the task as it was after an event (sequence number) or at a time.`,
+	Args: cobra.ExactArgs(1),
+	Run:  showTask,
+}
+
+func init() {
+	showCmd.Flags().BoolVar(&showFiles, "files", false, "List the files the task changed")
+	showCmd.Flags().BoolVar(&showFull, "full", false, "Print the full result and prompt instead of a preview")
+	showCmd.Flags().BoolVar(&showJSON, "json", false, "Print the details as JSON")
+	showCmd.Flags().StringVar(&showAt, "at", "", "Show the task as it was after event N (#N) or at a time (RFC 3339 or YYYY-MM-DD)")
+}
+
+func showTask(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	h, err := openHearth(workspaceDir)
+	if err != nil {
+		fatal("Failed to load hearth: %v", err)
+	}
+
+	at, err := parseAt(showAt)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	details, err := h.TaskDetailsAt(args[0], at)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	if showJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(details); err != nil {
+			fatal("Failed to encode task: %v", err)
+		}
+		return
+	}
+
+	printTaskDetails(details, workspaceDir)
+}
+
+// printTaskDetails renders a task's details for humans
+func printTaskDetails(details *hearth.TaskDetails, workspaceDir string) {
+	task := details.Task
+
+	fmt.Printf("%s [%s] %s\n", statusIcon(task.Status), task.ID, task.Title)
+	fmt.Printf("   Status: %s\n", task.Status)
+	if task.Description != "" {
+		fmt.Printf("   Description: %s\n", task.Description)
+	}
+	if len(details.Parents) > 0 {
+		var chain []string
+		for _, parent := range details.Parents {
+			chain = append(chain, parent.ID)
+		}
+		fmt.Printf("   Parents: %s\n", strings.Join(chain, " › "))
+	}
+	fmt.Printf("   Agent: %s\n", details.Agent)
+	if task.Rung > 0 {
+		fmt.Printf("   Escalation rung: %d\n", task.Rung)
+	}
+	if details.Verify != "" {
+		fmt.Printf("   Verify: %s\n", details.Verify)
+	}
+	if details.Review {
+		fmt.Printf("   Review: enabled\n")
+	}
+	if len(details.Scope) > 0 {
+		fmt.Printf("   Scope: %s\n", strings.Join(details.Scope, ", "))
+	}
+	fmt.Printf("   Created: %s\n", formatTime(task.CreatedAt))
+	if task.CompletedAt != nil {
+		fmt.Printf("   Completed: %s\n", formatTime(*task.CompletedAt))
+	}
+	if len(task.Commits) > 0 {
+		var commits []string
+		for _, commit := range task.Commits {
+			commits = append(commits, hearth.ShortSHA(commit))
+		}
+		fmt.Printf("   Commits: %s\n", strings.Join(commits, ", "))
+	}
+	if task.Feedback != "" {
+		fmt.Printf("   Feedback: %s\n", firstLine(task.Feedback))
+	}
+
+	if len(details.Children) > 0 {
+		fmt.Println()
+		fmt.Println("Children:")
+		for _, child := range details.Children {
+			fmt.Printf("  %s [%s] %s\n", statusIcon(child.Status), child.ID, child.Title)
+		}
+	}
+
+	if len(details.WaitsFor) > 0 {
+		fmt.Println()
+		fmt.Println("Waits for:")
+		for _, dependency := range details.WaitsFor {
+			fmt.Printf("  %s [%s] %s\n", statusIcon(dependency.Status), dependency.ID, dependency.Title)
+		}
+	}
+
+	if len(details.History) > 0 {
+		fmt.Println()
+		fmt.Println("Status history:")
+		for _, change := range details.History {
+			fmt.Printf("  %s  %-12s #%d %s\n", formatTime(change.Time), change.Status, change.Seq, change.Event)
+		}
+	}
+
+	usage := details.Usage
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Printf("  Executions: %d, verifications: %d, review rounds: %d\n", usage.Executions, usage.Verifications, usage.ReviewRounds)
+	fmt.Printf("  Agent calls: %d (%s)\n", usage.AgentCalls, usage.AgentTime.Round(time.Millisecond))
+	if len(usage.Agents) > 0 {
+		fmt.Printf("  Agents: %s\n", strings.Join(usage.Agents, ", "))
+	}
+
+	if details.ResultPath != "" {
+		fmt.Println()
+		fmt.Printf("Result (%s):\n", relativePath(workspaceDir, details.ResultPath))
+		printPreview(details.Result)
+	}
+
+	if details.PromptPath != "" {
+		fmt.Println()
+		fmt.Printf("Last prompt (%s):\n", relativePath(workspaceDir, details.PromptPath))
+		printPreview(details.Prompt)
+	}
+
+	if showFiles {
+		fmt.Println()
+		printFileChanges(task.Files)
+	}
+
+	fmt.Println()
+	fmt.Println("Events:")
+	for _, record := range details.Events {
+		fmt.Printf("  #%-4d %s  %s\n", record.Seq, formatTime(record.Time), record.Type)
+		if description := describeEvent(record.Event); description != "" {
+			fmt.Printf("         %s\n", description)
+		}
+	}
+}
+
+// printPreview prints text indented, limited to previewLines unless --full was given
+func printPreview(text string) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	hidden := 0
+	if !showFull && len(lines) > previewLines {
+		hidden = len(lines) - previewLines
+		lines = lines[:previewLines]
+	}
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+	if hidden > 0 {
+		fmt.Printf("  ... %d more lines (use --full)\n", hidden)
+	}
+}
+
+// describeEvent summarizes the interesting fields of an event on one line ("" when there are none)
+func describeEvent(event atmos.Event) string {
+	switch e := event.(type) {
+	case *hearth.TaskCreated:
+		return e.Title
+	case *hearth.NextTaskSelected:
+		return e.Reason
+	case *hearth.TaskExecuted:
+		parts := []string{fmt.Sprintf("attempt %d", e.Attempt)}
+		if e.Agent != "" {
+			parts = append(parts, hearth.AgentSelection{Agent: e.Agent, Model: e.Model}.String())
+		}
+		if len(e.Files) > 0 {
+			parts = append(parts, fmt.Sprintf("%d files changed", len(e.Files)))
+		}
+		if e.Commit != "" {
+			parts = append(parts, "commit "+hearth.ShortSHA(e.Commit))
+		}
+		if e.Error != "" {
+			parts = append(parts, "error: "+firstLine(e.Error))
+		}
+		return strings.Join(parts, ", ")
+	case *hearth.TaskVerified:
+		return fmt.Sprintf("%s (exit %d)", e.Command, e.ExitCode)
+	case *hearth.VerificationFailed:
+		return fmt.Sprintf("%s (exit %d)", e.Command, e.ExitCode)
+	case *hearth.TaskReviewed:
+		if e.Error != "" {
+			return fmt.Sprintf("round %d: %s (error: %s)", e.Round, e.Verdict, firstLine(e.Error))
+		}
+		return fmt.Sprintf("round %d: %s", e.Round, e.Verdict)
+	case *hearth.TaskEscalated:
+		return fmt.Sprintf("rung %d: %s", e.Rung, hearth.AgentSelection{Agent: e.Agent, Model: e.Model}.String())
+	case *hearth.TaskFailed:
+		return firstLine(e.Error)
+	case *hearth.TaskReverted:
+		return fmt.Sprintf("%d commits reverted", len(e.Commits))
+	case *hearth.TaskMerged:
+		if e.Error != "" {
+			return fmt.Sprintf("%s: %s", e.Branch, firstLine(e.Error))
+		}
+		return fmt.Sprintf("%s into %s (%s)", e.Branch, e.Into, hearth.ShortSHA(e.Commit))
+	case *hearth.MergeConflict:
+		return fmt.Sprintf("%s: %s", e.Branch, strings.Join(e.Files, ", "))
+	case *hearth.SummaryRequested:
+		if e.Repair {
+			return "repair"
+		}
+	case *hearth.SummaryGenerated:
+		if e.Repair {
+			return hearth.AgentSelection{Agent: e.Agent, Model: e.Model}.String() + ", repair"
+		}
+		return hearth.AgentSelection{Agent: e.Agent, Model: e.Model}.String()
+	case *hearth.TaskDeleted:
+		if e.Undoes == 0 {
+			return "repair"
+		}
+		return fmt.Sprintf("undoes #%d", e.Undoes)
+	case *hearth.TaskReopened:
+		if e.Undoes == 0 {
+			return e.Status + ", repair"
+		}
+		return fmt.Sprintf("%s, undoes #%d", e.Status, e.Undoes)
+	case *hearth.EventsUndone:
+		var seqs []string
+		for _, seq := range e.Seqs {
+			seqs = append(seqs, fmt.Sprintf("#%d", seq))
+		}
+		return strings.Join(seqs, ", ")
+	}
+	return ""
+}
+
+// formatTime formats a timestamp for display in local time
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// relativePath shows a path relative to the workspace when possible
+func relativePath(workspaceDir, path string) string {
+	if rel, ok := strings.CutPrefix(path, workspaceDir+string(os.PathSeparator)); ok {
+		return rel
+	}
+	return path
+}
+
+// firstLine returns the first line of a message
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// printFileChanges lists changed files with a git-style status letter
+func printFileChanges(files []hearth.FileChange) {
+	if len(files) == 0 {
+		fmt.Println("No files changed.")
+		return
+	}
+
+	fmt.Println("Files changed:")
+	for _, file := range files {
+		letter := "M"
+		switch file.Status {
+		case hearth.FileAdded:
+			letter = "A"
+		case hearth.FileDeleted:
+			letter = "D"
+		}
+		fmt.Printf("  %s %s\n", letter, file.Path)
+	}
+}
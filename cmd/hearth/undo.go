@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var (
+	undoLast  int
+	undoUntil int64
+	undoYes   bool
+	undoDry   bool
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo recent events with compensating events",
+	Long: `Undo the most recent events (--last N, default 1) or every event from a sequence
+number onwards (--until N, see hearth events). Nothing is removed from the log: a task's
+creation is undone by deleting it, a status change by restoring the previous status.
+Events with side effects outside the log (executions, verifications, reviews, summaries,
+merges and reverts) cannot be undone. A preview is shown before anything is applied.`,
+	Args: cobra.NoArgs,
+	Run:  undoEvents,
+}
+
+func init() {
+	undoCmd.Flags().IntVarP(&undoLast, "last", "n", 1, "Number of most recent events to undo")
+	undoCmd.Flags().Int64Var(&undoUntil, "until", 0, "Undo every event from this sequence number onwards")
+	undoCmd.Flags().BoolVarP(&undoYes, "yes", "y", false, "Apply without asking for confirmation")
+	undoCmd.Flags().BoolVar(&undoDry, "dry-run", false, "Only show what would be undone")
+}
+
+func undoEvents(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	h, err := openHearth(workspaceDir)
+	if err != nil {
+		fatal("Failed to load hearth: %v", err)
+	}
+
+	var plan *hearth.UndoPlan
+	if cmd.Flags().Changed("until") {
+		plan, err = h.PlanUndoUntil(undoUntil)
+	} else {
+		plan, err = h.PlanUndoLast(undoLast)
+	}
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	fmt.Println("Undo:")
+	for _, step := range plan.Steps {
+		record := step.Event
+		line := fmt.Sprintf("  #%-5d %-18s", record.Seq, record.Type)
+		if record.TaskID != "" {
+			line += " " + record.TaskID
+		}
+		fmt.Printf("%s  →  %s\n", line, describeCompensation(step.Compensation))
+	}
+
+	if undoDry {
+		return
+	}
+	if !undoYes && !confirm("Apply?") {
+		fmt.Println("Nothing changed.")
+		return
+	}
+
+	if err := h.Undo(plan); err != nil {
+		fatal("%v", err)
+	}
+	fmt.Printf("✓ Undid %d event(s)\n", len(plan.Steps))
+}
+
+// describeCompensation explains what a compensating event does
+func describeCompensation(event atmos.Event) string {
+	switch e := event.(type) {
+	case *hearth.TaskDeleted:
+		return "delete task"
+	case *hearth.TaskReopened:
+		return "restore status " + e.Status
+	}
+	return "nothing to undo"
+}
+
+// confirm asks a yes/no question on stdin (no is the default)
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fmizzell/hearth"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the event log for tampering",
+	Long: `Check that .hearth/events.json is intact. Every event carries the hash of the event
+before it, so an edited, removed, inserted or reordered event breaks the chain; the last
+event is also recorded in .hearth/events.head, which reveals events removed from the end.
+Events written before hash chaining are counted but cannot be checked.`,
+	Args: cobra.NoArgs,
+	Run:  verifyLog,
+}
+
+func verifyLog(cmd *cobra.Command, args []string) {
+	workspaceDir, err := getWorkspaceDir()
+	if err != nil {
+		fatal("Failed to get workspace directory: %v", err)
+	}
+
+	report, err := hearth.VerifyLog(workspaceDir)
+	if err != nil {
+		fatal("Failed to verify event log: %v", err)
+	}
+	if report.Problem != nil {
+		fatal("Event log is not intact: %s", report.Problem)
+	}
+
+	fmt.Printf("✓ Event log intact: %d event(s)\n", report.Events)
+	if report.Unhashed > 0 {
+		fmt.Printf("  %d event(s) predate hash chaining and were not checked\n", report.Unhashed)
+	}
+	if report.Head != nil && report.Head.Hash != "" {
+		fmt.Printf("  Head: #%d %s\n", report.Head.Seq, report.Head.Hash[:12])
+	}
+}
@@ -0,0 +1,536 @@
+package hearth
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scheduler names accepted in workspace config
+const (
+	SchedulerDepthFirst = "depth-first"
+)
+
+// Config holds workspace configuration loaded from .hearth/config.yaml
+// Precedence (lowest to highest): defaults, config file, HEARTH_* env vars, CLI flags
+type Config struct {
+	Caller     CallerConfig           `yaml:"caller"`
+	Agent      string                 `yaml:"agent,omitempty"`  // default agent backend ("" = claude)
+	Agents     map[string]AgentConfig `yaml:"agents,omitempty"` // named agent backends
+	Model      string                 `yaml:"model,omitempty"`
+	Execute    KindConfig             `yaml:"execute,omitempty"` // defaults for task execution calls
+	Summary    KindConfig             `yaml:"summary,omitempty"` // defaults for summary calls
+	Verify     VerifyConfig           `yaml:"verify"`
+	Review     ReviewConfig           `yaml:"review"`
+	Escalation []KindConfig           `yaml:"escalation,omitempty"` // rungs tried in order when a task fails
+	Git        GitConfig              `yaml:"git"`
+	Scope      ScopeConfig            `yaml:"scope"`
+	Timeout    Duration               `yaml:"timeout"`
+	Retry      RetryConfig            `yaml:"retry"`
+	Scheduler  string                 `yaml:"scheduler"`
+	Context    ContextConfig          `yaml:"context"`
+	IDs        IDConfig               `yaml:"ids"`
+}
+
+// CallerConfig describes the agent CLI invoked for each task
+type CallerConfig struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// AgentConfig describes a named agent backend (see NewAgentCaller)
+type AgentConfig struct {
+	Type      string   `yaml:"type"`                  // claude, cli, openai or anthropic
+	Command   string   `yaml:"command,omitempty"`     // cli, claude: binary to run
+	Args      []string `yaml:"args,omitempty"`        // cli: argv template; claude: args before the prompt
+	URL       string   `yaml:"url,omitempty"`         // openai, anthropic: API base URL
+	APIKeyEnv string   `yaml:"api_key_env,omitempty"` // openai, anthropic: env var holding the API key
+	Model     string   `yaml:"model,omitempty"`       // overrides the workspace model
+	MaxTokens int      `yaml:"max_tokens,omitempty"`  // anthropic: response token limit
+}
+
+// apiKeyEnv returns the env var holding the API key, defaulting per backend type
+func (a AgentConfig) apiKeyEnv() string {
+	if a.APIKeyEnv != "" {
+		return a.APIKeyEnv
+	}
+	switch a.Type {
+	case AgentTypeOpenAI:
+		return "OPENAI_API_KEY"
+	case AgentTypeAnthropic:
+		return "ANTHROPIC_API_KEY"
+	}
+	return ""
+}
+
+// missingAPIKey returns the env var that should hold the agent's API key when it is unset
+// ("" when the key is set or not needed); OpenAI-compatible local servers need no key
+// unless api_key_env names one
+func (a AgentConfig) missingAPIKey() string {
+	required := a.Type == AgentTypeAnthropic || (a.Type == AgentTypeOpenAI && a.APIKeyEnv != "")
+	if !required || os.Getenv(a.apiKeyEnv()) != "" {
+		return ""
+	}
+	return a.apiKeyEnv()
+}
+
+// KindConfig sets the agent and model for one kind of call (execute or summary)
+// Tasks that choose their own agent or model override these
+type KindConfig struct {
+	Agent string `yaml:"agent,omitempty"`
+	Model string `yaml:"model,omitempty"`
+}
+
+// VerifyConfig controls the verification command run after each leaf task
+type VerifyConfig struct {
+	Command     string `yaml:"command,omitempty"` // default for tasks that set none ("" = no verification)
+	MaxAttempts int    `yaml:"max_attempts"`      // executions per escalation rung before escalating
+}
+
+// ReviewConfig controls the reviewer pass run before a leaf task completes
+type ReviewConfig struct {
+	Enabled   bool   `yaml:"enabled"`         // default for tasks that set none
+	Agent     string `yaml:"agent,omitempty"` // reviewer agent (overrides task choices)
+	Model     string `yaml:"model,omitempty"` // reviewer model (overrides task choices)
+	MaxRounds int    `yaml:"max_rounds"`      // change requests before the task completes anyway
+}
+
+// GitConfig controls how hearth records task changes in the workspace's git repository
+type GitConfig struct {
+	Checkpoint bool   `yaml:"checkpoint"`          // commit each task's changes with a Hearth-Task trailer
+	Isolation  string `yaml:"isolation,omitempty"` // none, root or task (see worktree.go)
+}
+
+// ScopeConfig controls what happens when a task changes files outside its declared scope
+type ScopeConfig struct {
+	Enforce string `yaml:"enforce"` // warn or fail
+}
+
+// RetryConfig controls retries when the agent call itself fails
+type RetryConfig struct {
+	MaxAttempts int      `yaml:"max_attempts"`
+	Backoff     Duration `yaml:"backoff"`
+}
+
+// ContextConfig controls what context is injected into task prompts
+type ContextConfig struct {
+	ParentChain   bool `yaml:"parent_chain"`
+	Siblings      bool `yaml:"siblings"`
+	MaxSiblings   int  `yaml:"max_siblings"`    // 0 = unlimited
+	MaxGoalLength int  `yaml:"max_goal_length"` // 0 = unlimited
+}
+
+// IDConfig controls how task IDs are generated
+type IDConfig struct {
+	Strategy string `yaml:"strategy"` // short, sequential, hierarchical, hash
+}
+
+// Duration is a time.Duration that reads and writes as "30s", "10m" in YAML
+type Duration time.Duration
+
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", node.Value, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// DefaultConfig returns the configuration used when no config file exists
+func DefaultConfig() *Config {
+	return &Config{
+		Caller: CallerConfig{
+			Command: "claude",
+			Args: []string{
+				"--print",                        // Non-interactive output
+				"--dangerously-skip-permissions", // Skip permission prompts (safe: sandboxed to workDir)
+			},
+		},
+		Timeout: Duration(30 * time.Minute),
+		Retry: RetryConfig{
+			MaxAttempts: 1,
+			Backoff:     Duration(5 * time.Second),
+		},
+		Scheduler: SchedulerDepthFirst,
+		Verify:    VerifyConfig{MaxAttempts: 3},
+		Review:    ReviewConfig{MaxRounds: 2},
+		Scope:     ScopeConfig{Enforce: ScopeWarn},
+		Context: ContextConfig{
+			ParentChain: true,
+			Siblings:    true,
+		},
+		IDs: IDConfig{Strategy: IDStrategyShort},
+	}
+}
+
+// ConfigPath returns the path of the workspace config file
+func ConfigPath(workspaceDir string) string {
+	return filepath.Join(workspaceDir, ".hearth", "config.yaml")
+}
+
+// ReadConfigFile reads .hearth/config.yaml on top of the defaults, ignoring env overrides
+// A missing file is not an error - the defaults are returned
+func ReadConfigFile(workspaceDir string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	data, err := os.ReadFile(ConfigPath(workspaceDir))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// LoadConfig reads the workspace config file and applies HEARTH_* env overrides
+func LoadConfig(workspaceDir string) (*Config, error) {
+	cfg, err := ReadConfigFile(workspaceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config to .hearth/config.yaml
+func (c *Config) Save(workspaceDir string) error {
+	path := ConfigPath(workspaceDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create .hearth directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks the config for values the runner cannot work with
+func (c *Config) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(c.Caller.Command) == "" {
+		problems = append(problems, "caller.command must not be empty")
+	}
+	if c.Timeout < 0 {
+		problems = append(problems, "timeout must not be negative")
+	}
+	if c.Retry.MaxAttempts < 1 {
+		problems = append(problems, "retry.max_attempts must be at least 1")
+	}
+	if c.Retry.Backoff < 0 {
+		problems = append(problems, "retry.backoff must not be negative")
+	}
+	if c.Scheduler != SchedulerDepthFirst {
+		problems = append(problems, fmt.Sprintf("scheduler %q is not supported (use %s)", c.Scheduler, SchedulerDepthFirst))
+	}
+	if c.Verify.MaxAttempts < 1 {
+		problems = append(problems, "verify.max_attempts must be at least 1")
+	}
+	if c.Review.MaxRounds < 0 {
+		problems = append(problems, "review.max_rounds must not be negative")
+	}
+	switch c.Git.Isolation {
+	case "", IsolationNone, IsolationRoot, IsolationTask:
+	default:
+		problems = append(problems, fmt.Sprintf("git.isolation %q is not supported (use none, root or task)", c.Git.Isolation))
+	}
+	if c.Scope.Enforce != ScopeWarn && c.Scope.Enforce != ScopeFail {
+		problems = append(problems, fmt.Sprintf("scope.enforce %q is not supported (use warn or fail)", c.Scope.Enforce))
+	}
+	if c.Context.MaxSiblings < 0 {
+		problems = append(problems, "context.max_siblings must not be negative")
+	}
+	if c.Context.MaxGoalLength < 0 {
+		problems = append(problems, "context.max_goal_length must not be negative")
+	}
+	if _, err := NewIDGenerator(c.IDs.Strategy); err != nil {
+		problems = append(problems, "ids.strategy: "+err.Error())
+	}
+	if c.Agent != "" && c.Agent != AgentClaude {
+		if _, ok := c.Agents[c.Agent]; !ok {
+			problems = append(problems, fmt.Sprintf("agent %q is not defined in agents", c.Agent))
+		}
+	}
+	for _, key := range []string{"execute.agent", "summary.agent", "review.agent"} {
+		agent, _ := c.Get(key)
+		if agent != "" && agent != AgentClaude {
+			if _, ok := c.Agents[agent]; !ok {
+				problems = append(problems, fmt.Sprintf("%s %q is not defined in agents", key, agent))
+			}
+		}
+	}
+	for i, rung := range c.Escalation {
+		if rung.Agent == "" && rung.Model == "" {
+			problems = append(problems, fmt.Sprintf("escalation[%d] must set agent or model", i))
+		}
+		if rung.Agent != "" && rung.Agent != AgentClaude {
+			if _, ok := c.Agents[rung.Agent]; !ok {
+				problems = append(problems, fmt.Sprintf("escalation[%d].agent %q is not defined in agents", i, rung.Agent))
+			}
+		}
+	}
+	for _, name := range sortedKeys(c.Agents) {
+		problems = append(problems, c.Agents[name].validate(name, c.Model)...)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validate checks an agent definition; workspaceModel is the model it falls back to
+func (a AgentConfig) validate(name, workspaceModel string) []string {
+	var problems []string
+	switch a.Type {
+	case AgentTypeClaude:
+	case AgentTypeCLI:
+		if strings.TrimSpace(a.Command) == "" {
+			problems = append(problems, fmt.Sprintf("agents.%s.command must not be empty", name))
+		}
+	case AgentTypeOpenAI, AgentTypeAnthropic:
+		// API backends have no default model (a missing key is reported when the agent is used)
+		if a.Model == "" && workspaceModel == "" {
+			problems = append(problems, fmt.Sprintf("agents.%s.model must be set (or the workspace model)", name))
+		}
+		if a.URL == "" && a.Type == AgentTypeOpenAI {
+			problems = append(problems, fmt.Sprintf("agents.%s.url must not be empty", name))
+		}
+		if u, err := url.Parse(a.URL); a.URL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			problems = append(problems, fmt.Sprintf("agents.%s.url %q is not an http(s) URL", name, a.URL))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("agents.%s.type %q is not supported (use claude, cli, openai or anthropic)", name, a.Type))
+	}
+	if a.MaxTokens < 0 {
+		problems = append(problems, fmt.Sprintf("agents.%s.max_tokens must not be negative", name))
+	}
+	return problems
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ============================================================================
+// KEYS - Dotted key access for `hearth config get/set`, env vars and CLI flags
+// ============================================================================
+
+// configKey reads and writes a single config value as a string
+type configKey struct {
+	get func(c *Config) string
+	set func(c *Config, value string) error
+}
+
+var configKeys = map[string]configKey{
+	"caller.command": {
+		get: func(c *Config) string { return c.Caller.Command },
+		set: func(c *Config, v string) error { c.Caller.Command = v; return nil },
+	},
+	"caller.args": {
+		get: func(c *Config) string { return strings.Join(c.Caller.Args, " ") },
+		set: func(c *Config, v string) error { c.Caller.Args = strings.Fields(v); return nil },
+	},
+	"agent": {
+		get: func(c *Config) string { return c.Agent },
+		set: func(c *Config, v string) error { c.Agent = v; return nil },
+	},
+	"model": {
+		get: func(c *Config) string { return c.Model },
+		set: func(c *Config, v string) error { c.Model = v; return nil },
+	},
+	"execute.agent": {
+		get: func(c *Config) string { return c.Execute.Agent },
+		set: func(c *Config, v string) error { c.Execute.Agent = v; return nil },
+	},
+	"execute.model": {
+		get: func(c *Config) string { return c.Execute.Model },
+		set: func(c *Config, v string) error { c.Execute.Model = v; return nil },
+	},
+	"summary.agent": {
+		get: func(c *Config) string { return c.Summary.Agent },
+		set: func(c *Config, v string) error { c.Summary.Agent = v; return nil },
+	},
+	"summary.model": {
+		get: func(c *Config) string { return c.Summary.Model },
+		set: func(c *Config, v string) error { c.Summary.Model = v; return nil },
+	},
+	"timeout": {
+		get: func(c *Config) string { return time.Duration(c.Timeout).String() },
+		set: func(c *Config, v string) error { return setDuration(&c.Timeout, v) },
+	},
+	"retry.max_attempts": {
+		get: func(c *Config) string { return strconv.Itoa(c.Retry.MaxAttempts) },
+		set: func(c *Config, v string) error { return setInt(&c.Retry.MaxAttempts, v) },
+	},
+	"retry.backoff": {
+		get: func(c *Config) string { return time.Duration(c.Retry.Backoff).String() },
+		set: func(c *Config, v string) error { return setDuration(&c.Retry.Backoff, v) },
+	},
+	"scheduler": {
+		get: func(c *Config) string { return c.Scheduler },
+		set: func(c *Config, v string) error { c.Scheduler = v; return nil },
+	},
+	"verify.command": {
+		get: func(c *Config) string { return c.Verify.Command },
+		set: func(c *Config, v string) error { c.Verify.Command = v; return nil },
+	},
+	"verify.max_attempts": {
+		get: func(c *Config) string { return strconv.Itoa(c.Verify.MaxAttempts) },
+		set: func(c *Config, v string) error { return setInt(&c.Verify.MaxAttempts, v) },
+	},
+	"review.enabled": {
+		get: func(c *Config) string { return strconv.FormatBool(c.Review.Enabled) },
+		set: func(c *Config, v string) error { return setBool(&c.Review.Enabled, v) },
+	},
+	"review.agent": {
+		get: func(c *Config) string { return c.Review.Agent },
+		set: func(c *Config, v string) error { c.Review.Agent = v; return nil },
+	},
+	"review.model": {
+		get: func(c *Config) string { return c.Review.Model },
+		set: func(c *Config, v string) error { c.Review.Model = v; return nil },
+	},
+	"review.max_rounds": {
+		get: func(c *Config) string { return strconv.Itoa(c.Review.MaxRounds) },
+		set: func(c *Config, v string) error { return setInt(&c.Review.MaxRounds, v) },
+	},
+	"git.checkpoint": {
+		get: func(c *Config) string { return strconv.FormatBool(c.Git.Checkpoint) },
+		set: func(c *Config, v string) error { return setBool(&c.Git.Checkpoint, v) },
+	},
+	"git.isolation": {
+		get: func(c *Config) string { return c.Git.Isolation },
+		set: func(c *Config, v string) error { c.Git.Isolation = v; return nil },
+	},
+	"scope.enforce": {
+		get: func(c *Config) string { return c.Scope.Enforce },
+		set: func(c *Config, v string) error { c.Scope.Enforce = v; return nil },
+	},
+	"context.parent_chain": {
+		get: func(c *Config) string { return strconv.FormatBool(c.Context.ParentChain) },
+		set: func(c *Config, v string) error { return setBool(&c.Context.ParentChain, v) },
+	},
+	"context.siblings": {
+		get: func(c *Config) string { return strconv.FormatBool(c.Context.Siblings) },
+		set: func(c *Config, v string) error { return setBool(&c.Context.Siblings, v) },
+	},
+	"context.max_siblings": {
+		get: func(c *Config) string { return strconv.Itoa(c.Context.MaxSiblings) },
+		set: func(c *Config, v string) error { return setInt(&c.Context.MaxSiblings, v) },
+	},
+	"context.max_goal_length": {
+		get: func(c *Config) string { return strconv.Itoa(c.Context.MaxGoalLength) },
+		set: func(c *Config, v string) error { return setInt(&c.Context.MaxGoalLength, v) },
+	},
+	"ids.strategy": {
+		get: func(c *Config) string { return c.IDs.Strategy },
+		set: func(c *Config, v string) error { c.IDs.Strategy = v; return nil },
+	},
+}
+
+// ConfigKeys returns all supported dotted config keys in sorted order
+func ConfigKeys() []string {
+	return sortedKeys(configKeys)
+}
+
+// Get returns the value of a dotted config key (e.g. "retry.max_attempts")
+func (c *Config) Get(key string) (string, error) {
+	k, ok := configKeys[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key: %s", key)
+	}
+	return k.get(c), nil
+}
+
+// Set parses and assigns the value of a dotted config key
+func (c *Config) Set(key, value string) error {
+	k, ok := configKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	if err := k.set(c, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// EnvVar returns the environment variable that overrides a config key
+// e.g. "retry.max_attempts" → HEARTH_RETRY_MAX_ATTEMPTS
+func EnvVar(key string) string {
+	return "HEARTH_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// ApplyEnv applies HEARTH_* overrides using the given lookup (usually os.LookupEnv)
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, key := range ConfigKeys() {
+		if value, ok := lookup(EnvVar(key)); ok {
+			if err := c.Set(key, value); err != nil {
+				return fmt.Errorf("%s: %w", EnvVar(key), err)
+			}
+		}
+	}
+	return nil
+}
+
+func setDuration(d *Duration, value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func setInt(i *int, value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
+
+func setBool(b *bool, value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
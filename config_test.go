@@ -0,0 +1,193 @@
+package hearth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfig_DefaultsAreValid tests that a workspace without config works out of the box
+func TestConfig_DefaultsAreValid(t *testing.T) {
+	cfg, err := LoadConfig(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, "claude", cfg.Caller.Command)
+	assert.Equal(t, SchedulerDepthFirst, cfg.Scheduler)
+}
+
+// TestConfig_FileAndEnvPrecedence tests that env vars override the config file
+func TestConfig_FileAndEnvPrecedence(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".hearth"), 0755))
+	assert.NoError(t, os.WriteFile(ConfigPath(tmpDir), []byte(`
+model: sonnet
+timeout: 90s
+retry:
+  max_attempts: 3
+context:
+  siblings: false
+`), 0644))
+
+	t.Setenv("HEARTH_MODEL", "opus")
+
+	cfg, err := LoadConfig(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "opus", cfg.Model)
+	assert.Equal(t, Duration(90*time.Second), cfg.Timeout)
+	assert.Equal(t, 3, cfg.Retry.MaxAttempts)
+	assert.False(t, cfg.Context.Siblings)
+	assert.True(t, cfg.Context.ParentChain) // default kept when not in file
+
+	// The file itself is untouched by env overrides
+	fileCfg, err := ReadConfigFile(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "sonnet", fileCfg.Model)
+}
+
+// TestConfig_SetGetSave tests dotted key access and round-tripping through the file
+func TestConfig_SetGetSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := DefaultConfig()
+
+	assert.NoError(t, cfg.Set("retry.backoff", "2s"))
+	assert.NoError(t, cfg.Set("caller.args", "--print --verbose"))
+	assert.Error(t, cfg.Set("retry.max_attempts", "many"))
+	assert.Error(t, cfg.Set("no.such.key", "x"))
+
+	value, err := cfg.Get("retry.backoff")
+	assert.NoError(t, err)
+	assert.Equal(t, "2s", value)
+
+	assert.NoError(t, cfg.Save(tmpDir))
+	loaded, err := ReadConfigFile(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, Duration(2*time.Second), loaded.Retry.Backoff)
+	assert.Equal(t, []string{"--print", "--verbose"}, loaded.Caller.Args)
+}
+
+// TestConfig_Validate tests that invalid values are reported together
+func TestConfig_Validate(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Retry.MaxAttempts = 0
+	cfg.Scheduler = "random"
+	cfg.IDs.Strategy = "nope"
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "retry.max_attempts")
+	assert.Contains(t, err.Error(), "scheduler")
+	assert.Contains(t, err.Error(), "ids.strategy")
+
+	_, err = NewHearthWithConfig("", cfg)
+	assert.Error(t, err)
+}
+
+// TestConfig_ValidateAPIAgents tests that API backends need a model and an http(s) URL, and
+// that a missing key only fails the agent's own calls
+func TestConfig_ValidateAPIAgents(t *testing.T) {
+	t.Setenv("SET_KEY", "secret")
+	t.Setenv("UNSET_KEY", "")
+
+	cfg := DefaultConfig()
+	cfg.Agents = map[string]AgentConfig{
+		"ok":      {Type: AgentTypeAnthropic, APIKeyEnv: "SET_KEY", Model: "claude-sonnet-4-5"},
+		"nomodel": {Type: AgentTypeAnthropic, APIKeyEnv: "SET_KEY"},
+		"nourl":   {Type: AgentTypeOpenAI, APIKeyEnv: "SET_KEY", Model: "llama3"},
+		"badurl":  {Type: AgentTypeOpenAI, URL: "localhost:11434", APIKeyEnv: "SET_KEY", Model: "llama3"},
+		"nokey":   {Type: AgentTypeOpenAI, URL: "http://localhost:11434/v1", APIKeyEnv: "UNSET_KEY", Model: "llama3"},
+	}
+
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "agents.ok")
+	assert.Contains(t, err.Error(), "agents.nomodel.model must be set")
+	assert.Contains(t, err.Error(), "agents.nourl.url must not be empty")
+	assert.Contains(t, err.Error(), `agents.badurl.url "localhost:11434" is not an http(s) URL`)
+	assert.NotContains(t, err.Error(), "agents.nokey")
+
+	// The workspace model is the fallback
+	cfg.Model = "llama3"
+	cfg.Agents = map[string]AgentConfig{"nomodel": cfg.Agents["nomodel"], "nokey": cfg.Agents["nokey"]}
+	assert.NoError(t, cfg.Validate())
+
+	caller, err := NewAgentCaller(cfg, "nokey", "")
+	assert.NoError(t, err)
+	_, err = caller.Call("prompt", "")
+	assert.EqualError(t, err, `agent "nokey": $UNSET_KEY is not set (api_key_env names the variable holding the API key)`)
+}
+
+// TestDefaultClaudeCaller_Argv tests that command, args and model come from config
+func TestDefaultClaudeCaller_Argv(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Caller.Command = "echo"
+	cfg.Caller.Args = []string{"-n", "agent"}
+	cfg.Model = "haiku"
+
+	output, err := NewClaudeCaller(cfg).Call("do the thing", t.TempDir())
+	assert.NoError(t, err)
+	assert.Equal(t, "agent --model haiku do the thing", output)
+}
+
+// flakyCaller fails a fixed number of times before succeeding
+type flakyCaller struct {
+	failures int
+	calls    int
+}
+
+func (c *flakyCaller) Call(prompt, workDir string) (string, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return "", errors.New("transient failure")
+	}
+	return "ok", nil
+}
+
+// TestRetryingCaller tests retries with linear backoff
+func TestRetryingCaller(t *testing.T) {
+	var slept []time.Duration
+	inner := &flakyCaller{failures: 2}
+	caller := &RetryingCaller{
+		Caller: inner,
+		Policy: RetryConfig{MaxAttempts: 3, Backoff: Duration(time.Second)},
+		Sleep:  func(d time.Duration) { slept = append(slept, d) },
+	}
+
+	response, err := caller.Call("prompt", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", response)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, slept)
+
+	inner = &flakyCaller{failures: 5}
+	caller.Caller = inner
+	_, err = caller.Call("prompt", "")
+	assert.Error(t, err)
+	assert.Equal(t, 3, inner.calls)
+}
+
+// TestBuildTaskContext_Policy tests that the context policy limits injected context
+func TestBuildTaskContext_Policy(t *testing.T) {
+	now := time.Now()
+	tasks := map[string]*Task{
+		"ROOT": {ID: "ROOT", Title: "Root", Description: "A very long root goal", Status: "todo", CreatedAt: now},
+		"S1":   {ID: "S1", Title: "Sibling 1", ParentID: strPtr("ROOT"), Status: "completed", CreatedAt: now.Add(1 * time.Second)},
+		"S2":   {ID: "S2", Title: "Sibling 2", ParentID: strPtr("ROOT"), Status: "completed", CreatedAt: now.Add(2 * time.Second)},
+		"ME":   {ID: "ME", Title: "Current", ParentID: strPtr("ROOT"), Status: "todo", CreatedAt: now.Add(3 * time.Second)},
+	}
+
+	policy := DefaultConfig().Context
+	policy.MaxSiblings = 1
+	policy.MaxGoalLength = 6
+
+	context := BuildTaskContextWithPolicy("ME", tasks, "", policy)
+	assert.Contains(t, context, "ROOT GOAL: A very...")
+	assert.Contains(t, context, "S2")
+	assert.NotContains(t, context, "S1")
+
+	policy.Siblings = false
+	context = BuildTaskContextWithPolicy("ME", tasks, "", policy)
+	assert.NotContains(t, context, "PREVIOUS SIBLING RESULTS")
+}
@@ -0,0 +1,303 @@
+package hearth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// EventTaskID returns the task an event concerns ("" for run-level events)
+func EventTaskID(event atmos.Event) string {
+	switch e := event.(type) {
+	case *TaskCreated:
+		return e.TaskID
+	case *TaskStarted:
+		return e.TaskID
+	case *TaskCompleted:
+		return e.TaskID
+	case *NextTaskSelected:
+		return e.TaskID
+	case *TaskExecuted:
+		return e.TaskID
+	case *TaskVerified:
+		return e.TaskID
+	case *VerificationFailed:
+		return e.TaskID
+	case *TaskReviewed:
+		return e.TaskID
+	case *TaskEscalated:
+		return e.TaskID
+	case *TaskFailed:
+		return e.TaskID
+	case *TaskReverted:
+		return e.TaskID
+	case *TaskMerged:
+		return e.TaskID
+	case *MergeConflict:
+		return e.TaskID
+	case *TaskDeleted:
+		return e.TaskID
+	case *TaskReopened:
+		return e.TaskID
+	case *SummaryRequested:
+		return e.ParentTaskID
+	case *SummaryGenerated:
+		return e.ParentTaskID
+	}
+	return ""
+}
+
+// EventRecord is an event with its metadata, as shown by `hearth show` and `hearth events`
+type EventRecord struct {
+	Seq    int64
+	Type   string
+	Time   time.Time
+	RunID  string      `json:",omitempty"`
+	TaskID string      `json:",omitempty"`
+	Event  atmos.Event `json:"Data"`
+}
+
+// Timestamped is implemented by events that record when they happened
+// Every Hearth event does
+type Timestamped interface {
+	Timestamp() time.Time
+}
+
+// NewEventRecord wraps an event for display
+func NewEventRecord(event atmos.Event) EventRecord {
+	record := EventRecord{Type: event.Type(), TaskID: EventTaskID(event), Event: event}
+	if t, ok := event.(Timestamped); ok {
+		record.Time = t.Timestamp()
+	}
+	if s, ok := event.(Sequenced); ok {
+		record.Seq = s.Sequence()
+	}
+	if r, ok := event.(RunTagged); ok {
+		record.RunID = r.Run()
+	}
+	return record
+}
+
+// StatusChange is one transition in a task's status history
+type StatusChange struct {
+	Status string
+	Time   time.Time
+	Seq    int64  // event that caused the change
+	Event  string // its type
+}
+
+// TaskUsage summarizes the work spent on a task
+type TaskUsage struct {
+	Executions    int           // task_executed events
+	AgentCalls    int           // logged agent calls (executions, summaries and reviews)
+	AgentTime     time.Duration // total duration of those calls
+	Verifications int           // verification runs
+	ReviewRounds  int           // reviewer verdicts
+	Agents        []string      // agent backends (and models) that worked on the task
+}
+
+// TaskDetails is everything recorded about a task
+type TaskDetails struct {
+	Task       *Task
+	Agent      AgentSelection // effective agent and model for the next execution
+	Verify     string         // effective verification command
+	Review     bool           // whether a reviewer checks the task
+	Scope      []string       // effective scope
+	Parents    []*Task        // root first
+	Children   []*Task        // in creation order
+	WaitsFor   []*Task        // unfinished tasks that run first (see taskDependencies)
+	History    []StatusChange
+	Events     []EventRecord
+	Usage      TaskUsage
+	ResultPath string // "" when the task has no result yet
+	Result     string
+	PromptPath string // latest archived prompt ("" when never executed)
+	Prompt     string
+}
+
+// TaskDetails collects a task's fields, hierarchy, status history, usage, result,
+// last prompt and every event that concerns it
+func (h *Hearth) TaskDetails(taskID string) (*TaskDetails, error) {
+	return h.TaskDetailsAt(taskID, HistoryPoint{})
+}
+
+// TaskDetailsAt collects a task's details as they were at a point in history
+// Files are only read up to the last agent call recorded by then; the result file
+// is only included for the present, since later executions overwrite it
+func (h *Hearth) TaskDetailsAt(taskID string, at HistoryPoint) (*TaskDetails, error) {
+	state, err := h.StateAt(at)
+	if err != nil {
+		return nil, err
+	}
+	tasks := state.Tasks
+	task := tasks[taskID]
+	if task == nil {
+		if at.IsPresent() {
+			return nil, fmt.Errorf("task not found: %s", taskID)
+		}
+		return nil, fmt.Errorf("task not found at %s: %s", at, taskID)
+	}
+
+	cfg := h.Config()
+	details := &TaskDetails{
+		Task:   task,
+		Agent:  SelectAgent(taskID, tasks, cfg, CallKindExecute),
+		Verify: VerifyCommand(taskID, tasks, cfg),
+		Review: ReviewEnabled(taskID, tasks, cfg),
+		Scope:  TaskScope(taskID, tasks),
+	}
+	for parent := parentOf(task, tasks); parent != nil; parent = parentOf(parent, tasks) {
+		details.Parents = append([]*Task{parent}, details.Parents...)
+	}
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID == taskID {
+			details.Children = append(details.Children, t)
+		}
+	}
+	sort.Slice(details.Children, func(i, j int) bool {
+		return details.Children[i].CreatedBefore(details.Children[j])
+	})
+	if task.Status != "completed" {
+		details.WaitsFor = taskDependencies(task, tasks)
+	}
+
+	events := h.EventsAt(at)
+	agents := make(map[string]bool)
+	replayed := newHearthState()
+	status := ""
+	lastAttempt := 0
+	for _, event := range events {
+		// Fold every event once, so each of the task's events sees the state it produced
+		replayed = foldEvent(replayed, event)
+		if EventTaskID(event) != taskID {
+			continue
+		}
+		record := NewEventRecord(event)
+		details.Events = append(details.Events, record)
+
+		switch e := event.(type) {
+		case *TaskExecuted:
+			details.Usage.Executions++
+			agents[AgentSelection{Agent: e.Agent, Model: e.Model}.String()] = true
+			lastAttempt = max(lastAttempt, e.Attempt)
+		case *TaskVerified:
+			details.Usage.Verifications++
+		case *TaskReviewed:
+			details.Usage.ReviewRounds++
+			lastAttempt = max(lastAttempt, e.Attempt)
+		case *SummaryGenerated:
+			agents[AgentSelection{Agent: e.Agent, Model: e.Model}.String()] = true
+			lastAttempt = max(lastAttempt, e.Attempt)
+		}
+
+		if t := replayed.Tasks[taskID]; t != nil && t.Status != status {
+			status = t.Status
+			details.History = append(details.History, StatusChange{
+				Status: status,
+				Time:   record.Time,
+				Seq:    record.Seq,
+				Event:  record.Type,
+			})
+		}
+	}
+	details.Usage.Agents = sortedKeys(agents)
+
+	workspaceDir, ok := h.engine.GetService("workspace_dir").(string)
+	if !ok {
+		return details, nil
+	}
+
+	attempts, err := ExecutionAttempts(workspaceDir, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !at.IsPresent() {
+		attempts = slices.DeleteFunc(attempts, func(attempt int) bool { return attempt > lastAttempt })
+	}
+	for _, attempt := range attempts {
+		details.Usage.AgentCalls++
+		details.Usage.AgentTime += logDuration(ExecutionLogPath(workspaceDir, taskID, attempt))
+	}
+	if len(attempts) > 0 {
+		path := PromptPath(workspaceDir, taskID, attempts[len(attempts)-1])
+		if data, err := os.ReadFile(path); err == nil {
+			details.PromptPath = path
+			details.Prompt = string(data)
+		}
+	}
+
+	if !at.IsPresent() {
+		return details, nil
+	}
+	path := filepath.Join(workspaceDir, ".hearth", "results", taskID+".md")
+	if data, err := os.ReadFile(path); err == nil {
+		details.ResultPath = path
+		details.Result = string(data)
+	}
+
+	return details, nil
+}
+
+// logDuration reads the duration line of an execution log (0 when missing)
+func logDuration(path string) time.Duration {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // end of the header
+		}
+		if value, ok := strings.CutPrefix(line, "duration: "); ok {
+			d, _ := time.ParseDuration(value)
+			return d
+		}
+	}
+	return 0
+}
+
+// taskDependencies returns the unfinished tasks that run before task
+// Hearth has no explicit dependencies: tasks run depth-first in creation order, so a task
+// waits for the unfinished earlier siblings of itself and of each of its parents, and
+// for the running tasks whose scope overlaps its own
+func taskDependencies(task *Task, tasks map[string]*Task) []*Task {
+	var dependencies []*Task
+	for t := task; t != nil; t = parentOf(t, tasks) {
+		var earlier []*Task
+		for _, other := range tasks {
+			if other.Status != "completed" && sameParent(other, t) && other.CreatedBefore(t) {
+				earlier = append(earlier, other)
+			}
+		}
+		sort.Slice(earlier, func(i, j int) bool {
+			return earlier[i].CreatedBefore(earlier[j])
+		})
+		dependencies = append(earlier, dependencies...)
+	}
+
+	for _, blocker := range scopeBlockers(task, tasks) {
+		if !slices.Contains(dependencies, blocker) {
+			dependencies = append(dependencies, blocker)
+		}
+	}
+	return dependencies
+}
+
+// sameParent reports whether two tasks are siblings (or both roots)
+func sameParent(a, b *Task) bool {
+	if a.ParentID == nil || b.ParentID == nil {
+		return a.ParentID == nil && b.ParentID == nil
+	}
+	return *a.ParentID == *b.ParentID
+}
@@ -0,0 +1,105 @@
+package hearth_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTaskDetails tests that task details cover hierarchy, status history, usage and events
+func TestTaskDetails(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Build feature", Subtasks: []hearthtest.Subtask{
+			{Title: "Design"},
+			{Title: "Implement"},
+		}},
+		{Title: "Implement", Response: "implemented"},
+	})
+	root := ws.AddTask("Build feature", "", "")
+
+	ws.Run()
+
+	details, err := ws.Hearth.TaskDetails(root + ".2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Implement", details.Task.Title)
+	if assert.Len(t, details.Parents, 1) {
+		assert.Equal(t, root, details.Parents[0].ID)
+	}
+	assert.Empty(t, details.Children)
+
+	var statuses []string
+	for _, change := range details.History {
+		statuses = append(statuses, change.Status)
+	}
+	assert.Equal(t, []string{"todo", "in-progress", "completed"}, statuses)
+
+	var types []string
+	for _, record := range details.Events {
+		assert.NotZero(t, record.Seq)
+		types = append(types, record.Type)
+	}
+	assert.Equal(t, []string{"task_created", "next_task_selected", "task_executed", "task_completed"}, types)
+
+	assert.Equal(t, 1, details.Usage.Executions)
+	assert.Equal(t, 1, details.Usage.AgentCalls)
+	assert.Equal(t, []string{"claude"}, details.Usage.Agents)
+	assert.Contains(t, details.Result, "implemented")
+	assert.Contains(t, details.Prompt, "Implement")
+
+	// The parent lists its children and includes its summary in the events
+	parent, err := ws.Hearth.TaskDetails(root)
+	assert.NoError(t, err)
+	if assert.Len(t, parent.Children, 2) {
+		assert.Equal(t, "Design", parent.Children[0].Title)
+		assert.Equal(t, "Implement", parent.Children[1].Title)
+	}
+	assert.Equal(t, 2, parent.Usage.AgentCalls) // execution and summary
+
+	// Details marshal to JSON with the event data
+	data, err := json.Marshal(details)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"Type":"task_executed"`)
+
+	_, err = ws.Hearth.TaskDetails("missing")
+	assert.Error(t, err)
+}
+
+// TestTaskDetails_WaitsFor tests that a task waits for the unfinished earlier siblings of
+// itself and its parents, and for running tasks with an overlapping scope
+func TestTaskDetails_WaitsFor(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, nil)
+	first := ws.AddTask("First", "", "")
+	second := ws.AddTask("Second", "", "")
+	design := ws.AddTask("Design", "", second)
+	implement := ws.AddTask("Implement", "", second)
+	h := ws.Hearth
+	running, scoped := "R", "S"
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: running, Title: "Running", Scope: []string{"src/api/**"}, Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: scoped, Title: "Scoped", ParentID: &design, Scope: []string{"src/**"}, Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.TaskCompleted{TaskID: first, Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.TaskStarted{TaskID: running, Time: h.Now()}))
+
+	waitsFor := func(id string) []string {
+		details, err := h.TaskDetails(id)
+		assert.NoError(t, err)
+		var ids []string
+		for _, task := range details.WaitsFor {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	assert.Empty(t, waitsFor(second))
+	assert.Equal(t, []string{design}, waitsFor(implement))
+	assert.Equal(t, []string{second}, waitsFor(running))
+	assert.Equal(t, []string{running}, waitsFor(scoped))
+}
+
+// TestEventTaskID tests that summary events are attributed to their parent task
+func TestEventTaskID(t *testing.T) {
+	assert.Equal(t, "T1", hearth.EventTaskID(&hearth.SummaryGenerated{ParentTaskID: "T1"}))
+	assert.Equal(t, "T2", hearth.EventTaskID(&hearth.TaskExecuted{TaskID: "T2"}))
+	assert.Equal(t, "", hearth.EventTaskID(&hearth.ExecuteTasksRequested{}))
+}
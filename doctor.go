@@ -0,0 +1,321 @@
+package hearth
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Checks run by hearth doctor
+const (
+	CheckEnvironment = "environment"
+	CheckEventLog    = "event log"
+	CheckOrphan      = "orphaned task"
+	CheckResult      = "result file"
+	CheckStuck       = "stuck task"
+	CheckSummary     = "unsummarized parent"
+)
+
+// defaultStuckAfter is how long the log must be quiet before in-progress work counts
+// as abandoned, when the workspace sets no agent timeout
+const defaultStuckAfter = 30 * time.Minute
+
+// Diagnosis is one problem found in a workspace
+type Diagnosis struct {
+	Check   string // which check found it (see the Check constants)
+	TaskID  string // task concerned ("" for workspace-wide problems)
+	Problem string
+	Hint    string        // how to fix it by hand (when there are no Fixes)
+	Fixes   []atmos.Event // corrective events emitted by Repair, in order
+	Warning bool          // worth knowing, but only a problem for some tasks (not counted as a problem)
+}
+
+// DiagnoseEnvironment reports the binaries hearth needs that are not on PATH: the agent
+// CLIs the workspace configures, and hearth itself, which agents run to add subtasks
+// API agents whose key is not set are reported as warnings
+func DiagnoseEnvironment(cfg *Config) []Diagnosis {
+	commands := []string{cfg.Caller.Command}
+	for _, name := range sortedKeys(cfg.Agents) {
+		agent := cfg.Agents[name]
+		if agent.Command != "" && (agent.Type == AgentTypeClaude || agent.Type == AgentTypeCLI) {
+			commands = append(commands, agent.Command)
+		}
+	}
+
+	var diagnoses []Diagnosis
+	seen := make(map[string]bool)
+	for _, command := range commands {
+		if command == "" || seen[command] {
+			continue
+		}
+		seen[command] = true
+		if _, err := exec.LookPath(command); err != nil {
+			diagnoses = append(diagnoses, Diagnosis{
+				Check:   CheckEnvironment,
+				Problem: fmt.Sprintf("agent command %q is not on PATH", command),
+				Hint:    "install it, or point caller.command (or the agent's command) at it",
+			})
+		}
+	}
+
+	// An unset key only fails the calls of its agent, which no task may use
+	for _, name := range sortedKeys(cfg.Agents) {
+		if env := cfg.Agents[name].missingAPIKey(); env != "" {
+			diagnoses = append(diagnoses, Diagnosis{
+				Check:   CheckEnvironment,
+				Problem: fmt.Sprintf("agent %q: $%s is not set, so its calls fail", name, env),
+				Hint:    "export it before hearth run, or point api_key_env at the variable holding the key",
+				Warning: true,
+			})
+		}
+	}
+
+	if _, err := exec.LookPath("hearth"); err != nil {
+		diagnoses = append(diagnoses, Diagnosis{
+			Check:   CheckEnvironment,
+			Problem: "hearth is not on PATH, so agents cannot add subtasks",
+			Hint:    "add the directory of the hearth binary to PATH (see Installation)",
+		})
+	}
+	return diagnoses
+}
+
+// DiagnoseEventLog reports an event log that does not parse or fails verification
+func DiagnoseEventLog(workspaceDir string) []Diagnosis {
+	report, err := VerifyLog(workspaceDir)
+	if err != nil {
+		return []Diagnosis{{Check: CheckEventLog, Problem: err.Error(), Hint: "check that .hearth/ is readable"}}
+	}
+	if report.Problem != nil {
+		return []Diagnosis{{
+			Check:   CheckEventLog,
+			Problem: "event log is not intact: " + report.Problem.String(),
+			Hint:    "restore .hearth/events.json from a backup; events that do not decode are ignored when loading",
+		}}
+	}
+	return nil
+}
+
+// DiagnoseTasks checks the task tree: orphaned tasks, missing or stale result files,
+// and - once the log has been quiet for stuckAfter, so no run is still working on them -
+// tasks left in progress and parents whose children all completed but were never summarized
+// stuckAfter <= 0 uses the agent timeout
+func (h *Hearth) DiagnoseTasks(stuckAfter time.Duration) []Diagnosis {
+	state := h.engine.GetState("hearth").(HearthState)
+	tasks := sortedTasks(state.Tasks)
+	now := h.Now()
+
+	children := make(map[string][]*Task)
+	for _, task := range tasks {
+		if task.ParentID != nil {
+			children[*task.ParentID] = append(children[*task.ParentID], task)
+		}
+	}
+
+	var diagnoses []Diagnosis
+	for _, task := range tasks {
+		if task.ParentID == nil || state.Tasks[*task.ParentID] != nil {
+			continue
+		}
+		// Only roots are scheduled, so the task and its subtasks can never run
+		var fixes []atmos.Event
+		for _, id := range subtreeNewestFirst(task, children) {
+			fixes = append(fixes, &TaskDeleted{TaskID: id, Time: now})
+		}
+		diagnoses = append(diagnoses, Diagnosis{
+			Check:   CheckOrphan,
+			TaskID:  task.ID,
+			Problem: fmt.Sprintf("parent %s does not exist, so the task can never run", *task.ParentID),
+			Hint:    "delete it and add it again under the right parent",
+			Fixes:   fixes,
+		})
+	}
+
+	if workspaceDir, ok := h.engine.GetService("workspace_dir").(string); ok {
+		diagnoses = append(diagnoses, h.diagnoseResults(workspaceDir, state.Tasks)...)
+	}
+
+	if stuckAfter <= 0 {
+		stuckAfter = time.Duration(h.Config().Timeout)
+	}
+	if stuckAfter <= 0 {
+		stuckAfter = defaultStuckAfter
+	}
+	last, ok := h.lastEventTime()
+	if !ok || now.Sub(last) < stuckAfter {
+		return diagnoses // a run may still be working
+	}
+	quiet := now.Sub(last).Round(time.Minute)
+
+	for _, task := range tasks {
+		if task.Status != "in-progress" || len(children[task.ID]) > 0 {
+			continue
+		}
+		diagnoses = append(diagnoses, Diagnosis{
+			Check:   CheckStuck,
+			TaskID:  task.ID,
+			Problem: fmt.Sprintf("in progress, but nothing was recorded for %s (the run was interrupted)", quiet),
+			Fixes:   []atmos.Event{&TaskReopened{TaskID: task.ID, Status: "todo", Time: now}},
+		})
+	}
+
+	// Summaries go last, so reopened tasks are settled first; a repair summary calls the
+	// agent and completes the parent, but does not resume the run
+	for _, task := range tasks {
+		if task.Status == "completed" || len(children[task.ID]) == 0 || !allChildrenCompleted(task.ID, state.Tasks) {
+			continue
+		}
+		diagnoses = append(diagnoses, Diagnosis{
+			Check:   CheckSummary,
+			TaskID:  task.ID,
+			Problem: "all subtasks completed, but the task was never summarized and completed",
+			Fixes:   []atmos.Event{&SummaryRequested{ParentTaskID: task.ID, Repair: true, Time: now}},
+		})
+	}
+
+	return diagnoses
+}
+
+// diagnoseResults reports result files missing for tasks whose result was stored, and stale
+// result files: of tasks that no longer exist, from before the task was reopened, or older
+// than the task's last stored result
+func (h *Hearth) diagnoseResults(workspaceDir string, tasks map[string]*Task) []Diagnosis {
+	stored := make(map[string]time.Time)   // when each task's result was last stored
+	reopened := make(map[string]time.Time) // when each task was last reopened
+	for _, event := range h.engine.GetEvents() {
+		switch e := event.(type) {
+		case *TaskExecuted:
+			if e.ResultPath != "" {
+				stored[e.TaskID] = e.Time
+			}
+		case *SummaryGenerated:
+			if e.SummaryPath != "" {
+				stored[e.ParentTaskID] = e.Time
+			}
+		case *TaskReopened:
+			if e.Status != "completed" {
+				reopened[e.TaskID] = e.Time
+			}
+		}
+	}
+
+	var diagnoses []Diagnosis
+	for _, task := range sortedTasks(tasks) {
+		storedAt, ok := stored[task.ID]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(workspaceDir, ".hearth", "results", task.ID+".md")
+		info, err := os.Stat(path)
+		switch {
+		case os.IsNotExist(err):
+			if task.Status == "completed" {
+				diagnoses = append(diagnoses, Diagnosis{
+					Check:   CheckResult,
+					TaskID:  task.ID,
+					Problem: "result file is missing, so later tasks and summaries cannot read it",
+					Hint:    "restore " + relativeTo(workspaceDir, path) + ", or undo the task's completion to run it again",
+				})
+			}
+		case err != nil:
+			continue
+		case reopened[task.ID].After(storedAt):
+			diagnoses = append(diagnoses, Diagnosis{
+				Check:   CheckResult,
+				TaskID:  task.ID,
+				Problem: "result file is from before the task was reopened, so it describes undone work",
+				Hint:    "remove " + relativeTo(workspaceDir, path) + " (the next run writes a new one)",
+			})
+		case info.ModTime().Before(storedAt):
+			diagnoses = append(diagnoses, Diagnosis{
+				Check:   CheckResult,
+				TaskID:  task.ID,
+				Problem: fmt.Sprintf("result file is older than the task's last result (%s)", storedAt.Format(time.RFC3339)),
+				Hint:    "restore the latest result to " + relativeTo(workspaceDir, path) + ", or undo the task's completion to run it again",
+			})
+		}
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(workspaceDir, ".hearth", "results"))
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() || tasks[id] != nil {
+			continue
+		}
+		path := filepath.Join(workspaceDir, ".hearth", "results", entry.Name())
+		diagnoses = append(diagnoses, Diagnosis{
+			Check:   CheckResult,
+			TaskID:  id,
+			Problem: "result file belongs to a task that no longer exists",
+			Hint:    "remove " + relativeTo(workspaceDir, path),
+		})
+	}
+	return diagnoses
+}
+
+// Repair emits the corrective events of diagnoses, in order, and returns how many were emitted
+// A summary is skipped when an earlier fix already completed the parent
+func (h *Hearth) Repair(diagnoses []Diagnosis) (int, error) {
+	emitted := 0
+	for _, diagnosis := range diagnoses {
+		for _, event := range diagnosis.Fixes {
+			if e, ok := event.(*SummaryRequested); ok {
+				if task := h.GetTask(e.ParentTaskID); task == nil || task.Status == "completed" {
+					continue
+				}
+			}
+			if err := h.Process(event); err != nil {
+				return emitted, fmt.Errorf("failed to record %s for %s: %w", event.Type(), diagnosis.TaskID, err)
+			}
+			emitted++
+		}
+	}
+	return emitted, nil
+}
+
+// lastEventTime returns when the last timestamped event was recorded
+func (h *Hearth) lastEventTime() (time.Time, bool) {
+	events := h.engine.GetEvents()
+	for i := len(events) - 1; i >= 0; i-- {
+		if e, ok := events[i].(Timestamped); ok {
+			return e.Timestamp(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sortedTasks returns tasks in creation order
+func sortedTasks(tasks map[string]*Task) []*Task {
+	sorted := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		sorted = append(sorted, task)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedBefore(sorted[j])
+	})
+	return sorted
+}
+
+// subtreeNewestFirst returns the IDs of a task and its descendants, subtasks before their parents
+func subtreeNewestFirst(task *Task, children map[string][]*Task) []string {
+	var ids []string
+	kids := children[task.ID]
+	for i := len(kids) - 1; i >= 0; i-- {
+		ids = append(ids, subtreeNewestFirst(kids[i], children)...)
+	}
+	return append(ids, task.ID)
+}
+
+// relativeTo returns path relative to dir when possible
+func relativeTo(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
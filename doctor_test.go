@@ -0,0 +1,153 @@
+package hearth
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDoctor_DiagnosesAndRepairsTaskTree tests that an interrupted run's leftovers are found
+// only once the log has gone quiet, and that Repair fixes them without resuming the run
+func TestDoctor_DiagnosesAndRepairsTaskTree(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := &fixedClock{now: start.Add(time.Minute)}
+	caller := &MockClaudeCaller{}
+	h, err := New(WithWorkspace(t.TempDir()), WithCaller(caller), WithOutput(io.Discard), WithClock(clock))
+	assert.NoError(t, err)
+
+	grandparent, parent, missing, orphan := "G", "G.1", "X", "O"
+	h.Engine().SetEvents([]atmos.Event{
+		&TaskCreated{EventMeta: EventMeta{Seq: 1}, TaskID: "G", Title: "Grandparent", Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 2}, TaskID: "G.1", Title: "Parent", ParentID: &grandparent, Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 3}, TaskID: "G.1.1", Title: "Child", ParentID: &parent, Time: start},
+		&TaskCompleted{EventMeta: EventMeta{Seq: 4}, TaskID: "G.1.1", Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 5}, TaskID: "O", Title: "Orphan", ParentID: &missing, Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 6}, TaskID: "O.1", Title: "Orphan child", ParentID: &orphan, Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 7}, TaskID: "L", Title: "Leaf", Time: start},
+		&NextTaskSelected{EventMeta: EventMeta{Seq: 8}, TaskID: "L", Time: start},
+		&TaskCreated{EventMeta: EventMeta{Seq: 9}, TaskID: "T", Title: "Pending", Time: start},
+	})
+
+	// A run may still be working: only the orphan is reported
+	diagnoses := h.DiagnoseTasks(0)
+	if assert.Len(t, diagnoses, 1) {
+		assert.Equal(t, CheckOrphan, diagnoses[0].Check)
+		assert.Equal(t, "O", diagnoses[0].TaskID)
+		if assert.Len(t, diagnoses[0].Fixes, 2) {
+			assert.Equal(t, "O.1", diagnoses[0].Fixes[0].(*TaskDeleted).TaskID)
+			assert.Equal(t, "O", diagnoses[0].Fixes[1].(*TaskDeleted).TaskID)
+		}
+	}
+
+	// Past the agent timeout the in-progress leaf and the unsummarized parent are abandoned
+	clock.now = start.Add(time.Hour)
+	diagnoses = h.DiagnoseTasks(0)
+	var checks []string
+	for _, diagnosis := range diagnoses {
+		checks = append(checks, diagnosis.Check+" "+diagnosis.TaskID)
+	}
+	assert.Equal(t, []string{"orphaned task O", "stuck task L", "unsummarized parent G.1"}, checks)
+
+	before := len(h.Engine().GetEvents())
+	emitted, err := h.Repair(diagnoses)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, emitted)
+
+	// The summaries completed the parent and, in turn, the grandparent; nothing else ran
+	statuses := make(map[string]string)
+	for id, task := range h.GetTasks() {
+		statuses[id] = task.Status
+	}
+	assert.Equal(t, map[string]string{
+		"G": "completed", "G.1": "completed", "G.1.1": "completed", "L": "todo", "T": "todo",
+	}, statuses)
+	assert.Equal(t, 2, caller.CallCount)
+
+	var types []string
+	for _, event := range h.Engine().GetEvents()[before:] {
+		types = append(types, event.Type())
+	}
+	assert.Equal(t, []string{
+		"task_deleted", "task_deleted", "task_reopened",
+		"summary_requested", "summary_generated", "task_completed",
+		"summary_requested", "summary_generated", "task_completed",
+	}, types)
+
+	clock.now = start.Add(2 * time.Hour)
+	assert.Empty(t, h.DiagnoseTasks(0))
+}
+
+// TestDoctor_DiagnosesResultFiles tests that missing and stale result files are reported,
+// and that a current result is not
+func TestDoctor_DiagnosesResultFiles(t *testing.T) {
+	dir := t.TempDir()
+	h, err := New(WithWorkspace(dir), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+
+	now := h.Now()
+	h.Engine().SetEvents([]atmos.Event{
+		&TaskCreated{EventMeta: EventMeta{Seq: 1}, TaskID: "A", Title: "Executed", Time: now},
+		&TaskExecuted{EventMeta: EventMeta{Seq: 2}, TaskID: "A", ResultPath: ".hearth/results/A.md", Time: now},
+		&TaskCompleted{EventMeta: EventMeta{Seq: 3}, TaskID: "A", Time: now},
+		&TaskCreated{EventMeta: EventMeta{Seq: 4}, TaskID: "B", Title: "Completed by hand", Time: now},
+		&TaskCompleted{EventMeta: EventMeta{Seq: 5}, TaskID: "B", Time: now},
+		&TaskCreated{EventMeta: EventMeta{Seq: 6}, TaskID: "C", Title: "Current", Time: now},
+		&TaskExecuted{EventMeta: EventMeta{Seq: 7}, TaskID: "C", ResultPath: ".hearth/results/C.md", Time: now.Add(-time.Hour)},
+		&TaskCreated{EventMeta: EventMeta{Seq: 8}, TaskID: "R", Title: "Reopened", Time: now},
+		&TaskExecuted{EventMeta: EventMeta{Seq: 9}, TaskID: "R", ResultPath: ".hearth/results/R.md", Time: now.Add(-time.Hour)},
+		&TaskReopened{EventMeta: EventMeta{Seq: 10}, TaskID: "R", Status: "todo", Time: now},
+		&TaskCreated{EventMeta: EventMeta{Seq: 11}, TaskID: "S", Title: "Superseded", Time: now},
+		&TaskExecuted{EventMeta: EventMeta{Seq: 12}, TaskID: "S", ResultPath: ".hearth/results/S.md", Time: now},
+	})
+	for _, id := range []string{"C", "R", "S", "Z"} {
+		_, err = StoreTaskResult(dir, id, "result of "+id)
+		assert.NoError(t, err)
+	}
+	old := now.Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(filepath.Join(dir, ".hearth", "results", "S.md"), old, old))
+
+	diagnoses := h.DiagnoseTasks(0)
+	var found []string
+	for _, diagnosis := range diagnoses {
+		assert.Equal(t, CheckResult, diagnosis.Check)
+		assert.Empty(t, diagnosis.Fixes)
+		found = append(found, diagnosis.TaskID)
+	}
+	assert.Equal(t, []string{"A", "R", "S", "Z"}, found)
+	if assert.Len(t, diagnoses, 4) {
+		assert.Contains(t, diagnoses[0].Problem, "missing")
+		assert.Contains(t, diagnoses[1].Problem, "reopened")
+		assert.Contains(t, diagnoses[2].Problem, "older")
+		assert.Contains(t, diagnoses[3].Hint, filepath.Join(".hearth", "results", "Z.md"))
+	}
+
+	// The log itself is intact
+	assert.Empty(t, DiagnoseEventLog(dir))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".hearth", "events.json"), []byte("not json"), 0644))
+	assert.Len(t, DiagnoseEventLog(dir), 1)
+}
+
+// TestDoctor_WarnsAboutMissingAPIKeys tests that an unset key is a warning, not a problem,
+// since only the agent's own calls fail
+func TestDoctor_WarnsAboutMissingAPIKeys(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	cfg := DefaultConfig()
+	cfg.Caller.Command = "sh"
+	cfg.Agents = map[string]AgentConfig{"big": {Type: AgentTypeAnthropic, Model: "claude-x"}}
+	assert.NoError(t, cfg.Validate())
+
+	var warnings []Diagnosis
+	for _, diagnosis := range DiagnoseEnvironment(cfg) {
+		if diagnosis.Warning {
+			warnings = append(warnings, diagnosis)
+		}
+	}
+	if assert.Len(t, warnings, 1) {
+		assert.Contains(t, warnings[0].Problem, `agent "big": $ANTHROPIC_API_KEY is not set`)
+	}
+}
@@ -0,0 +1,58 @@
+package hearth
+
+import (
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/google/uuid"
+)
+
+// RunIDEnv is the environment variable carrying the current run's ID
+// `hearth run` sets it so events recorded by agents (e.g. `hearth add`) belong to the run
+const RunIDEnv = "HEARTH_RUN_ID"
+
+// NewRunID generates an ID for a `hearth run` invocation
+func NewRunID() string {
+	return "R-" + uuid.New().String()[:8]
+}
+
+// EventFilter selects events from the log; zero fields match everything
+type EventFilter struct {
+	TaskID   string    // events concerning this task (see EventTaskID)
+	Types    []string  // event types, e.g. task_executed
+	Since    time.Time // events at or after this time
+	Until    time.Time // events before this time
+	RunID    string    // events emitted during this run
+	AfterSeq int64     // events with a higher sequence number (used to follow the log)
+}
+
+// Matches reports whether an event passes the filter
+func (f EventFilter) Matches(event atmos.Event) bool {
+	record := NewEventRecord(event)
+	switch {
+	case f.TaskID != "" && EventTaskID(event) != f.TaskID:
+		return false
+	case len(f.Types) > 0 && !contains(f.Types, record.Type):
+		return false
+	case !f.Since.IsZero() && record.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !record.Time.Before(f.Until):
+		return false
+	case f.RunID != "" && record.RunID != f.RunID:
+		return false
+	case f.AfterSeq > 0 && record.Seq <= f.AfterSeq:
+		return false
+	}
+	return true
+}
+
+// Events returns the logged events that pass the filter, in log order
+func (h *Hearth) Events(filter EventFilter) []EventRecord {
+	var records []EventRecord
+	for _, event := range h.engine.GetEvents() {
+		if filter.Matches(event) {
+			records = append(records, NewEventRecord(event))
+		}
+	}
+	return records
+}
@@ -0,0 +1,48 @@
+package hearth
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEvents_TagsRunAndFilters tests that events carry the instance's run ID and that
+// filters select by task, type, time, run and sequence
+func TestEvents_TagsRunAndFilters(t *testing.T) {
+	clock := fixedClock{now: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	dir := t.TempDir()
+
+	// A task added outside a run, then a run that executes it
+	h, err := New(WithWorkspace(dir), WithClock(clock), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "A", Title: "A", Time: h.Now()}))
+
+	run, err := New(WithWorkspace(dir), WithClock(clock), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard), WithRunID("R-1"))
+	assert.NoError(t, err)
+	assert.NoError(t, run.Process(&ExecuteTasksRequested{Time: run.Now()}))
+
+	all := h.Events(EventFilter{})
+	assert.Equal(t, "", all[0].RunID)
+	for _, record := range all[1:] {
+		assert.Equal(t, "R-1", record.RunID, record.Type)
+	}
+
+	types := func(records []EventRecord) []string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Type)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"task_created", "next_task_selected", "task_executed", "task_completed"},
+		types(h.Events(EventFilter{TaskID: "A"})))
+	assert.Equal(t, []string{"task_executed"}, types(h.Events(EventFilter{Types: []string{"task_executed"}})))
+	assert.Len(t, h.Events(EventFilter{RunID: "R-1"}), len(all)-1)
+	assert.Len(t, h.Events(EventFilter{AfterSeq: 2}), len(all)-2)
+	assert.Len(t, h.Events(EventFilter{Since: clock.now}), len(all))
+	assert.Empty(t, h.Events(EventFilter{Until: clock.now}))
+	assert.Empty(t, h.Events(EventFilter{Since: clock.now.Add(time.Second)}))
+}
@@ -4,10 +4,16 @@ import "time"
 
 // TaskCreated event
 type TaskCreated struct {
+	EventMeta
 	TaskID      string
 	Title       string
 	Description string
 	ParentID    *string
+	Verify      string   // command that must pass before the task completes ("" = inherit)
+	Review      *bool    // review this task and its subtasks (nil = inherit)
+	Agent       string   // agent backend for this task and its subtasks ("" = inherit)
+	Model       string   // model for this task and its subtasks ("" = inherit)
+	Scope       []string // path globs the task may touch (nil = inherit)
 	Time        time.Time
 }
 
@@ -16,6 +22,7 @@ func (e *TaskCreated) Timestamp() time.Time { return e.Time }
 
 // TaskStarted event
 type TaskStarted struct {
+	EventMeta
 	TaskID string
 	Time   time.Time
 }
@@ -25,6 +32,7 @@ func (e *TaskStarted) Timestamp() time.Time { return e.Time }
 
 // TaskCompleted event
 type TaskCompleted struct {
+	EventMeta
 	TaskID string
 	Time   time.Time
 }
@@ -38,6 +46,7 @@ func (e *TaskCompleted) Timestamp() time.Time { return e.Time }
 
 // ExecuteTasksRequested triggers the orchestration loop
 type ExecuteTasksRequested struct {
+	EventMeta
 	Time time.Time
 }
 
@@ -46,6 +55,7 @@ func (e *ExecuteTasksRequested) Timestamp() time.Time { return e.Time }
 
 // NextTaskSelected represents scheduler picking next task
 type NextTaskSelected struct {
+	EventMeta
 	TaskID string // empty if no tasks available
 	Reason string // why this task was selected
 	Time   time.Time
@@ -56,17 +66,146 @@ func (e *NextTaskSelected) Timestamp() time.Time { return e.Time }
 
 // TaskExecuted represents task execution completion
 type TaskExecuted struct {
+	EventMeta
 	TaskID     string
-	ResultPath string // path to result file
+	ResultPath string       // path to result file
+	Attempt    int          // execution attempt number (see .hearth/logs/<task-id>/)
+	LogPath    string       // path to the execution log of the last attempt
+	PromptHash string       // sha256 of the prompt sent (archived in .hearth/prompts/<task-id>/)
+	Agent      string       // agent backend that ran the task
+	Model      string       // model the task ran with ("" = backend default)
+	Error      string       // set when the agent call failed (after retries)
+	BaseCommit string       // HEAD before the task ran (git.checkpoint only)
+	Commit     string       // checkpoint commit of the task's changes ("" = no changes)
+	Worktree   string       // worktree the agent ran in (git.isolation only)
+	Files      []FileChange // files the agent created, modified or deleted
+	OutOfScope []string     // changed files outside the task's declared scope
 	Time       time.Time
 }
 
 func (e *TaskExecuted) Type() string         { return "task_executed" }
 func (e *TaskExecuted) Timestamp() time.Time { return e.Time }
 
+// TaskVerified records a run of the task's verification command
+type TaskVerified struct {
+	EventMeta
+	TaskID   string
+	Command  string // enriched by before hook
+	ExitCode int    // 0 = passed
+	Attempt  int    // verification run number for this task
+	LogPath  string // combined output (.hearth/verify/<task-id>/<attempt>.log)
+	Time     time.Time
+}
+
+func (e *TaskVerified) Type() string         { return "task_verified" }
+func (e *TaskVerified) Timestamp() time.Time { return e.Time }
+
+// VerificationFailed re-queues a task whose verification command failed
+// The output is fed back into the task's next prompt
+type VerificationFailed struct {
+	EventMeta
+	TaskID   string
+	Command  string
+	ExitCode int
+	Output   string // tail of the command output
+	Time     time.Time
+}
+
+func (e *VerificationFailed) Type() string         { return "verification_failed" }
+func (e *VerificationFailed) Timestamp() time.Time { return e.Time }
+
+// TaskReviewed records a reviewer's verdict on a task's result
+// Requested changes re-queue the task with the review as feedback
+type TaskReviewed struct {
+	EventMeta
+	TaskID     string
+	Round      int    // review round for this task, starting at 1
+	Verdict    string // approve, request_changes or none (enriched by before hook)
+	Comments   string // reviewer response (truncated), fed back on request_changes
+	ReviewPath string // .hearth/reviews/<task-id>.md
+	Agent      string // reviewer agent
+	Model      string // reviewer model
+	Attempt    int    // execution attempt number of the review call
+	LogPath    string // path to the execution log of the review call
+	PromptHash string // sha256 of the review prompt sent
+	Error      string // why there is no verdict: the reviewer could not be called or gave none (the task fails)
+	Time       time.Time
+}
+
+func (e *TaskReviewed) Type() string         { return "task_reviewed" }
+func (e *TaskReviewed) Timestamp() time.Time { return e.Time }
+
+// TaskEscalated moves a failed task to the next rung of the escalation ladder
+type TaskEscalated struct {
+	EventMeta
+	TaskID string
+	Rung   int    // 1-based index into the escalation config
+	Agent  string // agent the task will run with next
+	Model  string // model the task will run with next
+	Reason string // why the previous attempt failed
+	Time   time.Time
+}
+
+func (e *TaskEscalated) Type() string         { return "task_escalated" }
+func (e *TaskEscalated) Timestamp() time.Time { return e.Time }
+
+// TaskFailed marks a task as failed once it has no escalation rungs left
+type TaskFailed struct {
+	EventMeta
+	TaskID string
+	Error  string
+	Time   time.Time
+}
+
+func (e *TaskFailed) Type() string         { return "task_failed" }
+func (e *TaskFailed) Timestamp() time.Time { return e.Time }
+
+// TaskReverted records that a task's checkpoint commits were reverted (see `hearth revert`)
+type TaskReverted struct {
+	EventMeta
+	TaskID  string
+	Commits []string // checkpoint commits that were reverted
+	Reverts []string // revert commits created, in the same order
+	Time    time.Time
+}
+
+func (e *TaskReverted) Type() string         { return "task_reverted" }
+func (e *TaskReverted) Timestamp() time.Time { return e.Time }
+
+// TaskMerged merges an isolated task's worktree branch back before the task completes
+type TaskMerged struct {
+	EventMeta
+	TaskID    string
+	Branch    string   // worktree branch that was merged (enriched by before hook)
+	Into      string   // directory merged into, relative to the workspace ("." = the workspace)
+	Commit    string   // merge commit
+	Conflicts []string // conflicting files when the merge failed
+	Error     string   // set when the merge failed
+	Time      time.Time
+}
+
+func (e *TaskMerged) Type() string         { return "task_merged" }
+func (e *TaskMerged) Timestamp() time.Time { return e.Time }
+
+// MergeConflict fails a task whose worktree could not be merged back
+// The worktree and branch are kept for manual resolution; the parent stays open
+type MergeConflict struct {
+	EventMeta
+	TaskID string
+	Branch string
+	Files  []string // conflicting files
+	Error  string
+	Time   time.Time
+}
+
+func (e *MergeConflict) Type() string         { return "merge_conflict" }
+func (e *MergeConflict) Timestamp() time.Time { return e.Time }
+
 // SummaryRequested is emitted when a parent task needs a summary (all children complete)
 type SummaryRequested struct {
+	EventMeta
 	ParentTaskID string
+	Repair       bool // requested by hearth doctor: complete the parent without resuming the run
 	Time         time.Time
 }
 
@@ -75,10 +214,55 @@ func (e *SummaryRequested) Timestamp() time.Time { return e.Time }
 
 // SummaryGenerated is emitted when a summary has been generated for a parent
 type SummaryGenerated struct {
+	EventMeta
 	ParentTaskID string
 	SummaryPath  string // enriched by before hook
+	Attempt      int    // execution attempt number of the summary call
+	LogPath      string // path to the execution log of the summary call
+	PromptHash   string // sha256 of the summary prompt sent
+	Agent        string // agent backend that wrote the summary
+	Model        string // model the summary was written with ("" = backend default)
+	Repair       bool   // see SummaryRequested.Repair
 	Time         time.Time
 }
 
 func (e *SummaryGenerated) Type() string         { return "summary_generated" }
 func (e *SummaryGenerated) Timestamp() time.Time { return e.Time }
+
+// ============================================================================
+// UNDO EVENTS - Compensate earlier events without rewriting the log (see `hearth undo`)
+// ============================================================================
+
+// TaskDeleted removes a task, compensating its TaskCreated event
+type TaskDeleted struct {
+	EventMeta
+	TaskID string
+	Undoes int64 // sequence number of the TaskCreated event (0 = a hearth doctor repair)
+	Time   time.Time
+}
+
+func (e *TaskDeleted) Type() string         { return "task_deleted" }
+func (e *TaskDeleted) Timestamp() time.Time { return e.Time }
+
+// TaskReopened restores the status a task had before an undone status change
+type TaskReopened struct {
+	EventMeta
+	TaskID string
+	Status string // status restored
+	Undoes int64  // sequence number of the undone event (0 = a hearth doctor repair)
+	Time   time.Time
+}
+
+func (e *TaskReopened) Type() string         { return "task_reopened" }
+func (e *TaskReopened) Timestamp() time.Time { return e.Time }
+
+// EventsUndone records which events an undo covered, including those that needed no compensation
+// It closes every `hearth undo`, so undone events are never selected again
+type EventsUndone struct {
+	EventMeta
+	Seqs []int64 // sequence numbers of the undone events, newest first
+	Time time.Time
+}
+
+func (e *EventsUndone) Type() string         { return "events_undone" }
+func (e *EventsUndone) Timestamp() time.Time { return e.Time }
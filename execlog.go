@@ -0,0 +1,150 @@
+package hearth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogsDir returns the directory holding execution logs for a task
+func LogsDir(workspaceDir, taskID string) string {
+	return filepath.Join(workspaceDir, ".hearth", "logs", taskID)
+}
+
+// ExecutionLogPath returns the log file for one execution attempt of a task
+func ExecutionLogPath(workspaceDir, taskID string, attempt int) string {
+	return filepath.Join(LogsDir(workspaceDir, taskID), fmt.Sprintf("%d.log", attempt))
+}
+
+// ExecutionAttempts returns the attempt numbers that have logs, in ascending order
+func ExecutionAttempts(workspaceDir, taskID string) ([]int, error) {
+	entries, err := os.ReadDir(LogsDir(workspaceDir, taskID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	var attempts []int
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".log")
+		if n, err := strconv.Atoi(name); err == nil && !entry.IsDir() {
+			attempts = append(attempts, n)
+		}
+	}
+	sort.Ints(attempts)
+	return attempts, nil
+}
+
+// WriteExecutionLog stores the full record of one agent call
+func WriteExecutionLog(workspaceDir, taskID string, attempt int, result *CallResult, callErr error) (string, error) {
+	dir := LogsDir(workspaceDir, taskID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "task: %s\n", taskID)
+	fmt.Fprintf(&b, "attempt: %d\n", attempt)
+
+	quoted := make([]string, len(result.Argv))
+	for i, arg := range result.Argv {
+		quoted[i] = strconv.Quote(arg)
+	}
+	fmt.Fprintf(&b, "argv: [%s]\n", strings.Join(quoted, ", "))
+	fmt.Fprintf(&b, "env: %s\n", strings.Join(result.Env, " "))
+	fmt.Fprintf(&b, "started: %s\n", result.Started.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "finished: %s\n", result.Finished.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "duration: %s\n", result.Finished.Sub(result.Started))
+	fmt.Fprintf(&b, "exit_code: %d\n", result.ExitCode)
+	if callErr != nil {
+		fmt.Fprintf(&b, "error: %s\n", firstLine(callErr.Error()))
+	}
+	b.WriteString("\n=== STDOUT ===\n")
+	b.WriteString(result.Output)
+	b.WriteString("\n=== STDERR ===\n")
+	b.WriteString(result.Stderr)
+
+	path := ExecutionLogPath(workspaceDir, taskID, attempt)
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write execution log: %w", err)
+	}
+
+	return path, nil
+}
+
+// LoggingCaller writes an execution log and archives the prompt for every call it forwards
+// Attempts are numbered per task, continuing after any existing logs
+type LoggingCaller struct {
+	Caller       AgentCaller
+	WorkspaceDir string
+	TaskID       string
+	Clock        Clock
+
+	// Set after each call
+	LastAttempt    int
+	LastLogPath    string
+	LastPromptHash string
+}
+
+func (c *LoggingCaller) Call(prompt, workDir string) (string, error) {
+	attempts, err := ExecutionAttempts(c.WorkspaceDir, c.TaskID)
+	if err != nil {
+		return "", err
+	}
+	attempt := 1
+	if len(attempts) > 0 {
+		attempt = attempts[len(attempts)-1] + 1
+	}
+
+	if _, err := ArchivePrompt(c.WorkspaceDir, c.TaskID, attempt, prompt); err != nil {
+		return "", err
+	}
+	c.LastPromptHash = PromptHash(prompt)
+
+	var result *CallResult
+	var callErr error
+	if detailed, ok := c.Caller.(DetailedCaller); ok {
+		result, callErr = detailed.CallDetailed(prompt, workDir)
+	} else {
+		// Plain callers only report output, so record what we can observe
+		clock := c.Clock
+		if clock == nil {
+			clock = SystemClock{}
+		}
+		result = &CallResult{Started: clock.Now()}
+		result.Output, callErr = c.Caller.Call(prompt, workDir)
+		result.Finished = clock.Now()
+		if callErr != nil {
+			result.ExitCode = -1
+		}
+	}
+	if result == nil {
+		result = &CallResult{ExitCode: -1}
+	}
+
+	logPath, err := WriteExecutionLog(c.WorkspaceDir, c.TaskID, attempt, result, callErr)
+	if err != nil {
+		return "", err
+	}
+	c.LastAttempt = attempt
+	c.LastLogPath = logPath
+
+	if callErr != nil {
+		return "", callErr
+	}
+	return result.Output, nil
+}
+
+// firstLine returns s up to the first newline
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package hearth
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExecutionLog_CapturesInvocation tests that each execution writes a log referenced by its event
+func TestExecutionLog_CapturesInvocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	caller := &DefaultClaudeCaller{
+		Command: "sh",
+		Args:    []string{"-c", "echo response; echo warning >&2"},
+		Env:     []string{"HEARTH_TEST=1"},
+	}
+
+	h, err := New(WithWorkspace(tmpDir), WithCaller(caller), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "Log me", Time: time.Now()}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: time.Now()}))
+
+	var executed *TaskExecuted
+	for _, e := range h.Engine().GetEvents() {
+		if te, ok := e.(*TaskExecuted); ok {
+			executed = te
+		}
+	}
+	assert.NotNil(t, executed)
+	assert.Equal(t, 1, executed.Attempt)
+	assert.Equal(t, ExecutionLogPath(tmpDir, "T-1", 1), executed.LogPath)
+
+	data, err := os.ReadFile(executed.LogPath)
+	assert.NoError(t, err)
+	log := string(data)
+	assert.Contains(t, log, `argv: ["sh", "-c", "echo response; echo warning >&2"`)
+	assert.Contains(t, log, "env: HEARTH_TEST=1")
+	assert.Contains(t, log, "exit_code: 0")
+	assert.Contains(t, log, "=== STDOUT ===\nresponse\n")
+	assert.Contains(t, log, "=== STDERR ===\nwarning\n")
+
+	// The result file holds only stdout
+	result, err := os.ReadFile(executed.ResultPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "response\n", string(result))
+}
+
+// TestExecutionLog_UsesInjectedClock tests that configured CLI backends and verification
+// commands are timed by the instance's clock
+func TestExecutionLog_UsesInjectedClock(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	cfg := DefaultConfig()
+	cfg.Agent = "echo"
+	cfg.Agents = map[string]AgentConfig{"echo": {Type: AgentTypeCLI, Command: "echo"}}
+
+	h, err := New(WithWorkspace(tmpDir), WithConfig(cfg), WithClock(&fixedClock{now: now}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "Timed", Time: now}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: now}))
+
+	data, err := os.ReadFile(ExecutionLogPath(tmpDir, "T-1", 1))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "started: "+now.Format(time.RFC3339Nano)+"\n")
+	assert.Contains(t, string(data), "duration: 0s\n")
+
+	result, err := RunVerification("true", tmpDir, 0, &fixedClock{now: now})
+	assert.NoError(t, err)
+	assert.Equal(t, now, result.Started)
+	assert.Equal(t, now, result.Finished)
+}
+
+// TestExecutionLog_OneLogPerAttempt tests that retried calls get separate attempt logs
+func TestExecutionLog_OneLogPerAttempt(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.Retry = RetryConfig{MaxAttempts: 3}
+
+	h, err := New(WithWorkspace(tmpDir), WithConfig(cfg), WithCaller(&flakyCaller{failures: 2}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "Flaky", Time: time.Now()}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: time.Now()}))
+
+	attempts, err := ExecutionAttempts(tmpDir, "T-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+
+	first, err := os.ReadFile(ExecutionLogPath(tmpDir, "T-1", 1))
+	assert.NoError(t, err)
+	assert.Contains(t, string(first), "exit_code: -1")
+	assert.Contains(t, string(first), "error: transient failure")
+
+	last, err := os.ReadFile(ExecutionLogPath(tmpDir, "T-1", 3))
+	assert.NoError(t, err)
+	assert.Contains(t, string(last), "exit_code: 0")
+	assert.Contains(t, string(last), "=== STDOUT ===\nok")
+}
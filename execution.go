@@ -1,72 +1,215 @@
 package hearth
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-
-	"github.com/fmizzell/hearth/prompts"
+	"time"
 )
 
-// ClaudeCaller is an interface for calling Claude (allows mocking in tests)
-type ClaudeCaller interface {
+// AgentCaller sends a prompt to an agent backend working in workDir and returns its response
+// Built-in backends: the claude CLI, any CLI via an argv template, and
+// OpenAI-compatible or Anthropic HTTP APIs (see agents.go)
+type AgentCaller interface {
 	Call(prompt, workDir string) (string, error)
 }
 
+// ClaudeCaller is the original name of AgentCaller, kept for compatibility
+type ClaudeCaller = AgentCaller
+
+// CallResult captures everything about a single agent invocation
+type CallResult struct {
+	Output   string    // stdout - the agent's response
+	Stderr   string    // stderr
+	ExitCode int       // process exit code (-1 if it never ran or was killed)
+	Argv     []string  // full command line including the prompt
+	Env      []string  // environment overrides (KEY=VALUE) on top of the inherited environment
+	Started  time.Time // when the call started
+	Finished time.Time // when the call returned
+}
+
+// DetailedCaller is implemented by callers that can report the full invocation
+// (used for per-attempt execution logs)
+type DetailedCaller interface {
+	CallDetailed(prompt, workDir string) (*CallResult, error)
+}
+
 // DefaultClaudeCaller uses the claude CLI
-type DefaultClaudeCaller struct{}
+// Zero-value fields fall back to the defaults from DefaultConfig
+type DefaultClaudeCaller struct {
+	Command string        // binary to run (default "claude")
+	Args    []string      // arguments placed before the prompt
+	Model   string        // passed as --model when set
+	Timeout time.Duration // 0 = no timeout
+	Env     []string      // extra KEY=VALUE environment variables
+	Clock   Clock         // timestamps the call (nil = the wall clock)
+}
+
+// NewClaudeCaller creates a caller from the workspace config
+func NewClaudeCaller(cfg *Config) *DefaultClaudeCaller {
+	return &DefaultClaudeCaller{
+		Command: cfg.Caller.Command,
+		Args:    cfg.Caller.Args,
+		Model:   cfg.Model,
+		Timeout: time.Duration(cfg.Timeout),
+	}
+}
 
 func (c *DefaultClaudeCaller) Call(prompt, workDir string) (string, error) {
-	cmd := exec.Command("claude",
-		"--print",                        // Non-interactive output
-		"--dangerously-skip-permissions", // Skip permission prompts (safe: sandboxed to workDir)
-		prompt,
-	)
+	result, err := c.CallDetailed(prompt, workDir)
+	if err != nil {
+		return "", err
+	}
+	return result.Output, nil
+}
+
+// CallDetailed runs the CLI and captures stdout, stderr, exit code and timings
+// The result is returned even when the command fails
+func (c *DefaultClaudeCaller) CallDetailed(prompt, workDir string) (*CallResult, error) {
+	return runCommand(c.Clock, c.command(), c.argv(prompt), workDir, c.Env, c.Timeout)
+}
+
+// runCommand runs an agent CLI in workDir, capturing stdout, stderr, exit code and timings
+// (read from clock; nil = the wall clock). The result is returned even when the command fails
+func runCommand(clock Clock, command string, argv []string, workDir string, env []string, timeout time.Duration) (*CallResult, error) {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	// Set Claude's working directory
+	cmd := exec.CommandContext(ctx, command, argv...)
+
+	// Set the agent's working directory
 	cmd.Dir = workDir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	// Capture output
-	output, err := cmd.CombinedOutput()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := &CallResult{
+		Argv:    append([]string{command}, argv...),
+		Env:     env,
+		Started: clock.Now(),
+	}
+	err := cmd.Run()
+	result.Finished = clock.Now()
+	result.Output = stdout.String()
+	result.Stderr = stderr.String()
+	result.ExitCode = -1
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	name := filepath.Base(command)
+	if ctx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("%s command timed out after %s\nOutput: %s%s", name, timeout, result.Output, result.Stderr)
+	}
 	if err != nil {
-		return "", fmt.Errorf("claude command failed: %w\nOutput: %s", err, string(output))
+		return result, fmt.Errorf("%s command failed: %w\nOutput: %s%s", name, err, result.Output, result.Stderr)
 	}
 
-	return string(output), nil
+	return result, nil
+}
+
+func (c *DefaultClaudeCaller) command() string {
+	if c.Command == "" {
+		return DefaultConfig().Caller.Command
+	}
+	return c.Command
+}
+
+// argv builds the arguments: configured args, optional model, then the prompt
+func (c *DefaultClaudeCaller) argv(prompt string) []string {
+	args := c.Args
+	if args == nil {
+		args = DefaultConfig().Caller.Args
+	}
+
+	argv := append([]string{}, args...)
+	if c.Model != "" {
+		argv = append(argv, "--model", c.Model)
+	}
+	return append(argv, prompt)
+}
+
+// RetryingCaller retries failed calls according to a retry policy
+type RetryingCaller struct {
+	Caller AgentCaller
+	Policy RetryConfig
+	Sleep  func(time.Duration) // defaults to time.Sleep
+}
+
+func (c *RetryingCaller) Call(prompt, workDir string) (string, error) {
+	sleep := c.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	attempts := c.Policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var response string
+		response, err = c.Caller.Call(prompt, workDir)
+		if err == nil {
+			return response, nil
+		}
+		if attempt < attempts {
+			sleep(time.Duration(c.Policy.Backoff) * time.Duration(attempt))
+		}
+	}
+
+	if attempts > 1 {
+		return "", fmt.Errorf("giving up after %d attempts: %w", attempts, err)
+	}
+	return "", err
 }
 
 // ExecuteTask handles task execution: builds context, calls Claude, stores result
 // This is the business logic extracted from cmd/hearth/run.go for reuse in orchestration
 func ExecuteTask(taskID string, tasks map[string]*Task, workspaceDir string, claudeCaller ClaudeCaller) (string, error) {
+	return ExecuteTaskWithPolicy(taskID, tasks, workspaceDir, claudeCaller, DefaultConfig().Context)
+}
+
+// ExecuteTaskWithPolicy is ExecuteTask with a context policy (the workspace's context section)
+func ExecuteTaskWithPolicy(taskID string, tasks map[string]*Task, workspaceDir string, claudeCaller AgentCaller, policy ContextConfig) (string, error) {
+	return ExecuteTaskIn(taskID, tasks, workspaceDir, workspaceDir, claudeCaller, policy)
+}
+
+// ExecuteTaskIn is ExecuteTask with the agent working in workDir (e.g. an isolated worktree)
+// Context and results still come from and go to the workspace
+func ExecuteTaskIn(taskID string, tasks map[string]*Task, workspaceDir, workDir string, claudeCaller AgentCaller, policy ContextConfig) (string, error) {
 	task := tasks[taskID]
 	if task == nil {
 		return "", fmt.Errorf("task not found: %s", taskID)
 	}
 
-	// Build context: parent chain + sibling results
-	contextInfo := BuildTaskContext(taskID, tasks, workspaceDir)
-
-	// Build full prompt with task context and instructions
-	taskContext := fmt.Sprintf(`
-CURRENT TASK: %s
-CURRENT TASK ID: %s
-
-IMPORTANT: Before starting work, assess if this task should be broken into subtasks.
-If this task involves multiple steps or can be parallelized, you MUST create subtasks first.
-
-`, task.Title, task.ID)
-
-	prompt := task.Description
-	if prompt == "" {
-		prompt = task.Title // Fallback to title if no description
+	// Build full prompt: context + task + instructions
+	fullPrompt, err := BuildTaskPrompt(taskID, tasks, workspaceDir, policy)
+	if err != nil {
+		return "", err
 	}
 
-	fullPrompt := contextInfo + taskContext + prompt + "\n" + prompts.TaskSystemInstructions
-
 	// Call Claude with the task description as the prompt
-	response, err := claudeCaller.Call(fullPrompt, workspaceDir)
+	response, err := claudeCaller.Call(fullPrompt, workDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to call Claude: %w", err)
 	}
@@ -82,6 +225,12 @@ If this task involves multiple steps or can be parallelized, you MUST create sub
 
 // BuildTaskContext builds context for a task including parent chain and sibling results
 func BuildTaskContext(taskID string, tasks map[string]*Task, workspaceDir string) string {
+	return BuildTaskContextWithPolicy(taskID, tasks, workspaceDir, DefaultConfig().Context)
+}
+
+// BuildTaskContextWithPolicy is BuildTaskContext with a context policy, which controls
+// which sections are included and how large they may get
+func BuildTaskContextWithPolicy(taskID string, tasks map[string]*Task, workspaceDir string, policy ContextConfig) string {
 	task := tasks[taskID]
 	if task == nil {
 		return ""
@@ -96,12 +245,12 @@ func BuildTaskContext(taskID string, tasks map[string]*Task, workspaceDir string
 		root := parentChain[len(parentChain)-1]
 		context.WriteString(fmt.Sprintf("ROOT TASK: %s\n", root.Title))
 		if root.Description != "" {
-			context.WriteString(fmt.Sprintf("ROOT GOAL: %s\n", root.Description))
+			context.WriteString(fmt.Sprintf("ROOT GOAL: %s\n", truncate(root.Description, policy.MaxGoalLength)))
 		}
 		context.WriteString("\n")
 
 		// Show parent hierarchy if there are intermediate parents
-		if len(parentChain) > 1 {
+		if policy.ParentChain && len(parentChain) > 1 {
 			context.WriteString("PARENT CHAIN:\n")
 			// Walk from root down to immediate parent
 			for i := len(parentChain) - 1; i >= 0; i-- {
@@ -114,10 +263,10 @@ func BuildTaskContext(taskID string, tasks map[string]*Task, workspaceDir string
 	}
 
 	// Find and list completed sibling results
-	if task.ParentID != nil {
+	if policy.Siblings && task.ParentID != nil {
 		var completedSiblings []*Task
 
-		// Get siblings that completed before this task (by creation time)
+		// Get siblings that completed before this task (by creation order)
 		for _, t := range tasks {
 			if t.ParentID != nil && *t.ParentID == *task.ParentID {
 				if t.ID != task.ID && t.Status == "completed" {
@@ -126,12 +275,23 @@ func BuildTaskContext(taskID string, tasks map[string]*Task, workspaceDir string
 			}
 		}
 
+		// Oldest first; when limited, keep the most recent siblings
+		sort.Slice(completedSiblings, func(i, j int) bool {
+			return completedSiblings[i].CreatedBefore(completedSiblings[j])
+		})
+		if policy.MaxSiblings > 0 && len(completedSiblings) > policy.MaxSiblings {
+			completedSiblings = completedSiblings[len(completedSiblings)-policy.MaxSiblings:]
+		}
+
 		if len(completedSiblings) > 0 {
 			context.WriteString("PREVIOUS SIBLING RESULTS:\n")
 			context.WriteString("Your siblings have already completed work. You can reference their findings:\n\n")
 			for _, sibling := range completedSiblings {
 				resultPath := fmt.Sprintf(".hearth/results/%s.md", sibling.ID)
 				context.WriteString(fmt.Sprintf("- %s \"%s\" → Result: %s\n", sibling.ID, sibling.Title, resultPath))
+				if len(sibling.Files) > 0 {
+					context.WriteString(fmt.Sprintf("  Files changed: %s\n", formatFileChanges(sibling.Files, maxContextFiles)))
+				}
 			}
 			context.WriteString("\nYou can read these files to avoid duplicating work and build on their findings.\n\n")
 		}
@@ -143,6 +303,30 @@ func BuildTaskContext(taskID string, tasks map[string]*Task, workspaceDir string
 	return ""
 }
 
+// maxContextFiles bounds the changed files listed per sibling in task context
+const maxContextFiles = 20
+
+// formatFileChanges lists changes as "path (status)", keeping at most max entries (0 = all)
+func formatFileChanges(changes []FileChange, max int) string {
+	var parts []string
+	for i, change := range changes {
+		if max > 0 && i == max {
+			parts = append(parts, fmt.Sprintf("and %d more", len(changes)-max))
+			break
+		}
+		parts = append(parts, change.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// truncate shortens s to max characters (0 = no limit)
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
+
 // buildParentChain walks up the task hierarchy and returns chain from immediate parent to root
 func buildParentChain(task *Task, tasks map[string]*Task) []*Task {
 	var chain []*Task
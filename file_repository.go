@@ -40,7 +40,8 @@ func (r *FileRepository) Add(engine *atmos.Engine, event atmos.Event) error {
 			return err
 		}
 
-		// Append new event
+		// Number and append new event (under the lock, so concurrent writers never share a number)
+		stampEvent(engine, event, existing)
 		existing = append(existing, event)
 
 		// Write all events back
@@ -125,6 +126,7 @@ func (r *FileRepository) readEvents(file *os.File, engine *atmos.Engine) ([]atmo
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal events: %w", err)
 	}
+	numberEvents(events)
 
 	return events, nil
 }
@@ -152,5 +154,6 @@ func (r *FileRepository) writeEvents(file *os.File, engine *atmos.Engine, events
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	return nil
+	// Record the head (still under the lock) so truncation can be detected
+	return writeLogHead(LogHeadPath(filepath.Dir(filepath.Dir(r.filePath))), events)
 }
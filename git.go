@@ -0,0 +1,238 @@
+package hearth
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TaskTrailer is the commit trailer that links a checkpoint commit to its task
+const TaskTrailer = "Hearth-Task"
+
+// hearthPathspec keeps hearth's own files out of checkpoint commits and diffs
+const hearthPathspec = ":(exclude).hearth"
+
+// runGit runs git in dir and returns its trimmed stdout
+func runGit(dir string, args ...string) (string, error) {
+	return runGitEnv(dir, nil, args...)
+}
+
+// runGitEnv runs git in dir with extra environment variables
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsGitRepo reports whether dir is inside a git work tree
+func IsGitRepo(dir string) bool {
+	out, err := runGit(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// GitHead returns the commit HEAD points to ("" in a repository without commits)
+func GitHead(dir string) (string, error) {
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return "", nil
+	}
+	return runGit(dir, "rev-parse", "HEAD")
+}
+
+// gitIdentity supplies a committer identity when the repository has none configured
+func gitIdentity(dir string) []string {
+	if email, err := runGit(dir, "config", "user.email"); err == nil && email != "" {
+		return nil
+	}
+	return []string{"-c", "user.name=hearth", "-c", "user.email=hearth@localhost"}
+}
+
+// CheckpointCommit commits the given paths in dir (the files a task changed) as the work of a task
+// Other changes in the work tree or the index, such as the user's own edits, are left alone.
+// The message carries a Hearth-Task trailer; returns "" when there was nothing to commit
+func CheckpointCommit(dir, taskID, title string, paths []string) (string, error) {
+	pathspecs, err := checkpointPathspecs(dir, paths)
+	if err != nil || len(pathspecs) == 0 {
+		return "", err
+	}
+
+	if _, err := runGit(dir, append([]string{"add", "-A", "--"}, pathspecs...)...); err != nil {
+		return "", fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	// Nothing staged - the files are back to how they were
+	if _, err := runGit(dir, append([]string{"diff", "--cached", "--quiet", "--"}, pathspecs...)...); err == nil {
+		return "", nil
+	}
+
+	message := fmt.Sprintf("%s\n\n%s: %s", title, TaskTrailer, taskID)
+	args := append(gitIdentity(dir), "commit", "--no-verify", "-q", "-m", message, "--only", "--")
+	if _, err := runGit(dir, append(args, pathspecs...)...); err != nil {
+		return "", fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	return GitHead(dir)
+}
+
+// checkpointPathspecs turns paths into literal pathspecs, dropping the ones git cannot stage:
+// files that were created and removed again without ever being tracked
+func checkpointPathspecs(dir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	var pathspecs []string
+	for _, path := range paths {
+		pathspecs = append(pathspecs, ":(literal)"+path)
+	}
+	out, err := runGit(dir, append([]string{"ls-files", "-z", "--"}, pathspecs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+	tracked := make(map[string]bool)
+	for _, path := range strings.Split(out, "\x00") {
+		tracked[path] = true
+	}
+
+	var stageable []string
+	for i, path := range paths {
+		if _, err := os.Lstat(filepath.Join(dir, path)); err == nil || tracked[path] {
+			stageable = append(stageable, pathspecs[i])
+		}
+	}
+	return stageable, nil
+}
+
+// RevertCommits reverts the given commits in dir, newest first, creating one revert commit each
+// A revert that conflicts is aborted and leaves the work tree as it was before that commit
+func RevertCommits(dir, taskID string, commits []string) ([]string, error) {
+	var reverts []string
+	for i := len(commits) - 1; i >= 0; i-- {
+		args := append(gitIdentity(dir), "revert", "--no-edit", commits[i])
+		if _, err := runGit(dir, args...); err != nil {
+			runGit(dir, "revert", "--abort")
+			return reverts, fmt.Errorf("failed to revert %s of task %s: %w", ShortSHA(commits[i]), taskID, err)
+		}
+		head, err := GitHead(dir)
+		if err != nil {
+			return reverts, err
+		}
+		reverts = append(reverts, head)
+	}
+	return reverts, nil
+}
+
+// gitDiff returns the changes in workDir since base (HEAD when base is ""), excluding .hearth
+// Returns "" outside a git repository
+func gitDiff(workDir, base string) string {
+	if base == "" {
+		base = "HEAD"
+	}
+	cmd := exec.Command("git", "diff", base, "--", ".", hearthPathspec)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return truncate(string(output), maxDiffLength)
+}
+
+// ShortSHA abbreviates a commit SHA for display
+func ShortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+// RevertTask reverts the checkpoint commits of a task and its subtasks, newest first,
+// and records a TaskReverted event for every task whose commits were undone
+func (h *Hearth) RevertTask(taskID string) ([]*TaskReverted, error) {
+	state := h.engine.GetState("hearth").(HearthState)
+	if state.Tasks[taskID] == nil {
+		return nil, fmt.Errorf("task not found: %s", taskID)
+	}
+	workspaceDir, ok := h.engine.GetService("workspace_dir").(string)
+	if !ok {
+		return nil, fmt.Errorf("no workspace to revert in")
+	}
+
+	// Checkpoint commits of the task's subtree in the order they were made
+	var commits, owners []string
+	for _, event := range h.engine.GetEvents() {
+		executed, ok := event.(*TaskExecuted)
+		if !ok || executed.Commit == "" || !inSubtree(executed.TaskID, taskID, state.Tasks) {
+			continue
+		}
+		if task := state.Tasks[executed.TaskID]; task != nil && contains(task.Commits, executed.Commit) {
+			commits = append(commits, executed.Commit)
+			owners = append(owners, executed.TaskID)
+		}
+	}
+	if len(commits) == 0 {
+		return nil, fmt.Errorf("task %s has no checkpoint commits to revert", taskID)
+	}
+
+	reverts, revertErr := RevertCommits(workspaceDir, taskID, commits)
+
+	// Record what was undone, even when a later revert failed
+	var events []*TaskReverted
+	byTask := make(map[string]*TaskReverted)
+	for i := range reverts {
+		commit := commits[len(commits)-1-i]
+		owner := owners[len(commits)-1-i]
+		event := byTask[owner]
+		if event == nil {
+			event = &TaskReverted{TaskID: owner, Time: h.Now()}
+			byTask[owner] = event
+			events = append(events, event)
+		}
+		event.Commits = append(event.Commits, commit)
+		event.Reverts = append(event.Reverts, reverts[i])
+	}
+	for _, event := range events {
+		if err := h.Process(event); err != nil {
+			return events, fmt.Errorf("failed to record revert of %s: %w", event.TaskID, err)
+		}
+	}
+
+	return events, revertErr
+}
+
+// inSubtree reports whether id is root or one of its descendants
+func inSubtree(id, root string, tasks map[string]*Task) bool {
+	for task := tasks[id]; task != nil; {
+		if task.ID == root {
+			return true
+		}
+		if task.ParentID == nil {
+			return false
+		}
+		task = tasks[*task.ParentID]
+	}
+	return false
+}
+
+// contains reports whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package hearth_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// git runs a git command in dir and returns its trimmed output
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@localhost"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// TestGitCheckpoint_CommitsAndReverts tests that each task's changes are committed with
+// a Hearth-Task trailer and that reverting a task undoes only its commit
+func TestGitCheckpoint_CommitsAndReverts(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Add A", Files: map[string]string{"a.txt": "a"}},
+		{Title: "Add B", Files: map[string]string{"b.txt": "b"}},
+	})
+	git(t, ws.Dir, "init", "-q")
+	git(t, ws.Dir, "commit", "-q", "--allow-empty", "-m", "initial")
+	base := git(t, ws.Dir, "rev-parse", "HEAD")
+	assert.NoError(t, ws.Hearth.Config().Set("git.checkpoint", "true"))
+
+	a := ws.AddTask("Add A", "", "")
+	b := ws.AddTask("Add B", "", "")
+	noop := ws.AddTask("Look around", "", "")
+	ws.Run()
+
+	commits := make(map[string]string)
+	for _, event := range ws.Hearth.Engine().GetEvents() {
+		if executed, ok := event.(*hearth.TaskExecuted); ok {
+			commits[executed.TaskID] = executed.Commit
+			if executed.TaskID == a {
+				assert.Equal(t, base, executed.BaseCommit)
+			}
+		}
+	}
+	assert.NotEmpty(t, commits[a])
+	assert.NotEmpty(t, commits[b])
+	assert.Empty(t, commits[noop])
+	assert.Contains(t, git(t, ws.Dir, "log", "-1", "--format=%B", commits[a]), "Hearth-Task: "+a)
+	assert.Equal(t, "a.txt", git(t, ws.Dir, "show", "--name-only", "--format=", commits[a]))
+	assert.Equal(t, []string{commits[a]}, ws.Hearth.GetTask(a).Commits)
+
+	reverted, err := ws.Hearth.RevertTask(a)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, []string{commits[a]}, reverted[0].Commits)
+	assert.NoFileExists(t, filepath.Join(ws.Dir, "a.txt"))
+	assert.FileExists(t, filepath.Join(ws.Dir, "b.txt"))
+	assert.Empty(t, ws.Hearth.GetTask(a).Commits)
+
+	// Nothing left to revert
+	_, err = ws.Hearth.RevertTask(a)
+	assert.Error(t, err)
+	_, err = ws.Hearth.RevertTask(noop)
+	assert.Error(t, err)
+
+	assert.Empty(t, git(t, ws.Dir, "ls-files", ".hearth"))
+}
+
+// TestGitCheckpoint_LeavesOtherChangesAlone tests that a checkpoint commits only what the task
+// changed: the user's uncommitted work and another task's failed attempt are left out
+func TestGitCheckpoint_LeavesOtherChangesAlone(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Broken", Files: map[string]string{"leftover.txt": "half done"}},
+		{Title: "Work", Files: map[string]string{"work.txt": "done"}},
+	})
+	git(t, ws.Dir, "init", "-q")
+	assert.NoError(t, os.WriteFile(filepath.Join(ws.Dir, "user.txt"), []byte("v1"), 0644))
+	git(t, ws.Dir, "add", "user.txt")
+	git(t, ws.Dir, "commit", "-q", "-m", "initial")
+	assert.NoError(t, os.WriteFile(filepath.Join(ws.Dir, "user.txt"), []byte("v2"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(ws.Dir, "staged.txt"), []byte("mine"), 0644))
+	git(t, ws.Dir, "add", "staged.txt")
+
+	assert.NoError(t, ws.Hearth.Config().Set("git.checkpoint", "true"))
+	assert.NoError(t, ws.Hearth.Config().Set("scope.enforce", hearth.ScopeFail))
+	h := ws.Hearth
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: "T-1", Title: "Broken", Scope: []string{"src/**"}, Time: h.Now()}))
+	work := ws.AddTask("Work", "", "")
+	ws.Run()
+
+	ws.AssertTree(`
+		✗ Broken
+		✓ Work
+	`)
+	commit := h.GetTask(work).Commits
+	if assert.Len(t, commit, 1) {
+		assert.Equal(t, "work.txt", git(t, ws.Dir, "show", "--name-only", "--format=", commit[0]))
+	}
+	assert.Equal(t, "A  staged.txt\n M user.txt\n?? leftover.txt", git(t, ws.Dir, "status", "--porcelain", "--", ".", ":(exclude).hearth"))
+}
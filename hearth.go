@@ -2,7 +2,9 @@ package hearth
 
 import (
 	"errors"
+	"maps"
 	"sort"
+	"time"
 
 	"github.com/cumulusrpg/atmos"
 )
@@ -16,47 +18,113 @@ type Hearth struct {
 
 // NewHearth creates a new Hearth instance
 // If workspaceDir is empty, creates in-memory instance (for testing)
-// Otherwise, sets up file persistence and registers orchestration services
+// Otherwise, loads .hearth/config.yaml (plus env overrides), sets up file persistence
+// and registers orchestration services
 func NewHearth(workspaceDir string) (*Hearth, error) {
-	var opts []atmos.EngineOption
-
-	// Set up persistence if workspace provided
+	cfg := DefaultConfig()
 	if workspaceDir != "" {
-		repo, err := NewFileRepository(workspaceDir)
+		var err error
+		cfg, err = LoadConfig(workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewHearthWithConfig(workspaceDir, cfg)
+}
+
+// NewHearthWithConfig creates a Hearth instance using an explicit config
+func NewHearthWithConfig(workspaceDir string, cfg *Config) (*Hearth, error) {
+	return New(WithWorkspace(workspaceDir), WithConfig(cfg))
+}
+
+// New creates a Hearth instance configured by functional options
+// Without WithWorkspace or WithRepository the instance is in-memory
+func New(opts ...Option) (*Hearth, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.config == nil {
+		o.config = DefaultConfig()
+	}
+	cfg := o.config
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var engineOpts []atmos.EngineOption
+
+	// Set up persistence: explicit repository wins, otherwise the workspace event file
+	// Every repository numbers the events it stores (FileRepository does so itself)
+	if o.repository != nil {
+		engineOpts = append(engineOpts, atmos.WithRepository(&SequencedRepository{o.repository}))
+	} else if o.workspaceDir != "" {
+		repo, err := NewFileRepository(o.workspaceDir)
+		if err != nil {
+			return nil, err
+		}
+		engineOpts = append(engineOpts, atmos.WithRepository(repo))
+	} else {
+		engineOpts = append(engineOpts, atmos.WithRepository(&SequencedRepository{atmos.NewInMemoryRepository()}))
+	}
+
+	if o.idGenerator == nil {
+		idGenerator, err := NewIDGenerator(cfg.IDs.Strategy)
 		if err != nil {
 			return nil, err
 		}
-		opts = append(opts, atmos.WithRepository(repo))
+		o.idGenerator = idGenerator
 	}
 
-	engine := atmos.NewEngine(opts...)
+	if o.scheduler == nil {
+		scheduler, err := NewScheduler(cfg.Scheduler)
+		if err != nil {
+			return nil, err
+		}
+		o.scheduler = scheduler
+	}
+
+	engine := atmos.NewEngine(engineOpts...)
 
 	// Register initial state
-	engine.RegisterState("hearth", HearthState{
-		Tasks: make(map[string]*Task),
-	})
+	engine.RegisterState("hearth", newHearthState())
 
 	// Register event handlers
 	engine.When("task_created", func() atmos.Event { return &TaskCreated{} }).
-		Updates("hearth", reduceTaskCreated)
-
-	engine.When("task_started", func() atmos.Event { return &TaskStarted{} }).
-		Updates("hearth", reduceTaskStarted)
-
+		Requires(atmos.Valid(&TaskCreationValidator{}))
+	engine.When("task_started", func() atmos.Event { return &TaskStarted{} })
 	engine.When("task_completed", func() atmos.Event { return &TaskCompleted{} }).
-		Requires(atmos.Valid(&TaskCompletionValidator{})).
-		Updates("hearth", reduceTaskCompleted)
+		Requires(atmos.Valid(&TaskCompletionValidator{}))
 
 	// Setup event-driven orchestration
+	engine.When("verification_failed", func() atmos.Event { return &VerificationFailed{} })
+	engine.When("task_reviewed", func() atmos.Event { return &TaskReviewed{} })
+	engine.When("task_escalated", func() atmos.Event { return &TaskEscalated{} })
+	engine.When("task_failed", func() atmos.Event { return &TaskFailed{} })
+	engine.When("task_reverted", func() atmos.Event { return &TaskReverted{} })
+	engine.When("merge_conflict", func() atmos.Event { return &MergeConflict{} })
+	engine.When("task_deleted", func() atmos.Event { return &TaskDeleted{} })
+	engine.When("task_reopened", func() atmos.Event { return &TaskReopened{} })
+	engine.When("events_undone", func() atmos.Event { return &EventsUndone{} })
+
 	// Reducers
-	engine.When("next_task_selected").Updates("hearth", reduceNextTaskSelected)
-	engine.When("task_executed").Updates("hearth", reduceTaskExecuted)
+	for eventType, reducer := range hearthReducers {
+		engine.When(eventType).Updates("hearth", reducer)
+	}
 
 	// Before hooks (where work happens)
 	engine.When("next_task_selected").
 		Before(atmos.NewTypedListener(TypedListenerFunc[*NextTaskSelected](beforeNextTaskSelected)))
 	engine.When("task_executed").
 		Before(atmos.NewTypedListener(TypedListenerFunc[*TaskExecuted](beforeTaskExecuted)))
+	engine.When("task_verified", func() atmos.Event { return &TaskVerified{} }).
+		Before(atmos.NewTypedListener(TypedListenerFunc[*TaskVerified](beforeTaskVerified)))
+	engine.When("task_reviewed").
+		Before(atmos.NewTypedListener(TypedListenerFunc[*TaskReviewed](beforeTaskReviewed)))
+	engine.When("task_merged", func() atmos.Event { return &TaskMerged{} }).
+		Before(atmos.NewTypedListener(TypedListenerFunc[*TaskMerged](beforeTaskMerged)))
 	engine.When("summary_generated").
 		Before(atmos.NewTypedListener(TypedListenerFunc[*SummaryGenerated](beforeSummaryGenerated)))
 
@@ -67,6 +135,20 @@ func NewHearth(workspaceDir string) (*Hearth, error) {
 		Then(atmos.NewTypedListener(TypedListenerFunc[*NextTaskSelected](onNextTaskSelected)))
 	engine.When("task_executed").
 		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskExecuted](onTaskExecuted)))
+	engine.When("task_verified").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskVerified](onTaskVerified)))
+	engine.When("verification_failed").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*VerificationFailed](onVerificationFailed)))
+	engine.When("task_reviewed").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskReviewed](onTaskReviewed)))
+	engine.When("task_escalated").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskEscalated](onTaskEscalated)))
+	engine.When("task_failed").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskFailed](onTaskFailed)))
+	engine.When("task_merged").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*TaskMerged](onTaskMerged)))
+	engine.When("merge_conflict").
+		Then(atmos.NewTypedListener(TypedListenerFunc[*MergeConflict](onMergeConflict)))
 
 	// Parent auto-completion (always runs - replaces reducer mutation)
 	engine.When("task_completed").
@@ -90,10 +172,8 @@ func NewHearth(workspaceDir string) (*Hearth, error) {
 		engine: engine,
 	}
 
-	// Register services for orchestration if workspace provided
-	if workspaceDir != "" {
-		engine.RegisterService("workspace_dir", workspaceDir)
-		engine.RegisterService("claude_caller", &DefaultClaudeCaller{})
+	if err := o.register(engine); err != nil {
+		return nil, err
 	}
 
 	return h, nil
@@ -145,7 +225,7 @@ func (h *Hearth) GetChildTasks(parentID string) []*Task {
 	return children
 }
 
-// GetNextTask returns the next task to work on using depth-first traversal
+// GetNextTask returns the next task to work on according to the scheduler
 func (h *Hearth) GetNextTask() *Task {
 	state := h.engine.GetState("hearth").(HearthState)
 
@@ -155,7 +235,30 @@ func (h *Hearth) GetNextTask() *Task {
 		tasks = append(tasks, task)
 	}
 
-	return findNextTask(tasks)
+	return getScheduler(h.engine).Next(tasks)
+}
+
+// NextTaskID generates an ID for a new task using the configured ID strategy
+// The returned ID is guaranteed not to collide with any existing or deleted task, so the
+// events of a deleted task never mix with a new one's
+func (h *Hearth) NextTaskID(parentID *string, title, description string) string {
+	state := h.engine.GetState("hearth").(HearthState)
+	created := make(map[string]*Task, len(state.Tasks)+len(state.Deleted))
+	maps.Copy(created, state.Deleted)
+	maps.Copy(created, state.Tasks)
+	generator := getIDGenerator(h.engine)
+	return generator.NextID(created, parentID, title, description)
+}
+
+// Now returns the current time from the instance's clock
+// Use it to timestamp events so tests can inject a fixed clock
+func (h *Hearth) Now() time.Time {
+	return getClock(h.engine).Now()
+}
+
+// Config returns the configuration this instance was created with
+func (h *Hearth) Config() *Config {
+	return getConfig(h.engine)
 }
 
 // Engine exposes the underlying Atmos engine for advanced use cases
@@ -180,9 +283,9 @@ func findNextTask(tasks []*Task) *Task {
 		}
 	}
 
-	// Sort roots by creation time
+	// Sort roots by creation order
 	sort.Slice(roots, func(i, j int) bool {
-		return roots[i].CreatedAt.Before(roots[j].CreatedAt)
+		return roots[i].CreatedBefore(roots[j])
 	})
 
 	// Depth-first search through each root
@@ -205,9 +308,9 @@ func findNextInSubtree(parent *Task, taskMap map[string]*Task) *Task {
 		}
 	}
 
-	// Sort children by creation time
+	// Sort children by creation order
 	sort.Slice(children, func(i, j int) bool {
-		return children[i].CreatedAt.Before(children[j].CreatedAt)
+		return children[i].CreatedBefore(children[j])
 	})
 
 	if len(children) == 0 {
@@ -216,6 +319,11 @@ func findNextInSubtree(parent *Task, taskMap map[string]*Task) *Task {
 			return nil
 		}
 
+		// Wait while a running task may touch the same files
+		if scopeBlocked(parent, taskMap) {
+			return nil
+		}
+
 		return parent
 	}
 
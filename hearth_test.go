@@ -1,6 +1,7 @@
 package hearth
 
 import (
+	"io"
 	"testing"
 	"time"
 
@@ -18,15 +19,13 @@ import (
 // - Summary generation
 func TestHearthJourney(t *testing.T) {
 	// Create hearth with persistence (exercises FileRepository)
+	// and a mock Claude caller (avoid real API calls)
 	tmpDir := t.TempDir()
-	h, err := NewHearth(tmpDir)
+	mockCaller := &MockClaudeCaller{}
+	h, err := New(WithWorkspace(tmpDir), WithCaller(mockCaller), WithOutput(io.Discard))
 	assert.NoError(t, err)
 	assert.NotNil(t, h)
 
-	// Override with mock Claude caller (avoid real API calls)
-	mockCaller := &MockClaudeCaller{}
-	h.Engine().RegisterService("claude_caller", mockCaller)
-
 	// Create a hierarchical task structure:
 	// ROOT: "Improve authentication"
 	//   ├─ CHILD1: "Implement 2FA"
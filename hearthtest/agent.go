@@ -0,0 +1,208 @@
+// Package hearthtest provides helpers for testing Hearth orchestration, presets and hooks
+// without calling a real agent: a rule-driven fake agent, temporary workspaces and tree assertions.
+package hearthtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fmizzell/hearth"
+)
+
+// Rule describes how the fake agent reacts to a call
+// A rule matches when all of its set matchers match; the first matching rule wins
+type Rule struct {
+	Title   string          // exact task title ("" matches any title)
+	Summary bool            // match summary calls instead of executions
+	Review  bool            // match review calls instead of executions
+	Match   func(Call) bool // optional custom matcher
+
+	Subtasks       []Subtask         // subtasks to create under the task (as `hearth add -p` would)
+	Files          map[string]string // files to write, relative to the call's working directory
+	FailOnAttempts []int             // attempts (1-based, per task and call kind) that return an error
+	Response       string            // response text (defaults to a canned message; reviews approve)
+}
+
+// Subtask is a task the fake agent creates
+type Subtask struct {
+	Title       string
+	Description string
+	Verify      string // as `hearth add --verify`
+	Agent       string // as `hearth add --agent`
+	Model       string // as `hearth add --model`
+}
+
+// Call records one invocation of the fake agent
+type Call struct {
+	TaskID  string
+	Title   string
+	Summary bool // true for summary (synthesis) calls
+	Review  bool // true for reviewer calls
+	Attempt int  // per task and call kind, starting at 1
+	Prompt  string
+	WorkDir string
+}
+
+var (
+	executeHeader = regexp.MustCompile(`(?m)^CURRENT TASK: (.*)\nCURRENT TASK ID: (\S+)$`)
+	summaryHeader = regexp.MustCompile(`(?m)^ORIGINAL TASK: (.*)\nTASK ID: (\S+)$`)
+	reviewHeader  = regexp.MustCompile(`(?m)^REVIEWED TASK: (.*)\nTASK ID: (\S+)$`)
+)
+
+// Agent is a fake hearth.AgentCaller driven by rules
+type Agent struct {
+	WorkspaceDir string // workspace the agent adds subtasks to
+	Rules        []Rule
+
+	mu       sync.Mutex
+	calls    []Call
+	attempts map[string]int
+}
+
+// NewAgent creates a fake agent for a workspace
+func NewAgent(workspaceDir string, rules ...Rule) *Agent {
+	return &Agent{WorkspaceDir: workspaceDir, Rules: rules}
+}
+
+// Calls returns every call made so far, in order
+func (a *Agent) Calls() []Call {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Call{}, a.calls...)
+}
+
+// CalledTitles returns the task titles of all calls, summaries and reviews suffixed
+// with " (summary)" and " (review)"
+func (a *Agent) CalledTitles() []string {
+	var titles []string
+	for _, call := range a.Calls() {
+		title := call.Title
+		if call.Summary {
+			title += " (summary)"
+		}
+		if call.Review {
+			title += " (review)"
+		}
+		titles = append(titles, title)
+	}
+	return titles
+}
+
+func (a *Agent) Call(prompt, workDir string) (string, error) {
+	call, err := parseCall(prompt, workDir)
+	if err != nil {
+		return "", err
+	}
+
+	a.mu.Lock()
+	if a.attempts == nil {
+		a.attempts = make(map[string]int)
+	}
+	key := fmt.Sprintf("%s/%t/%t", call.TaskID, call.Summary, call.Review)
+	a.attempts[key]++
+	call.Attempt = a.attempts[key]
+	a.calls = append(a.calls, call)
+	a.mu.Unlock()
+
+	rule := a.match(call)
+	if rule == nil {
+		return defaultResponse(call), nil
+	}
+
+	for _, attempt := range rule.FailOnAttempts {
+		if attempt == call.Attempt {
+			return "", fmt.Errorf("fake agent: scripted failure for %s on attempt %d", call.TaskID, call.Attempt)
+		}
+	}
+
+	for name, content := range rule.Files {
+		path := filepath.Join(workDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	if len(rule.Subtasks) > 0 {
+		if err := a.addSubtasks(call.TaskID, rule.Subtasks); err != nil {
+			return "", err
+		}
+	}
+
+	if rule.Response != "" {
+		return rule.Response, nil
+	}
+	return defaultResponse(call), nil
+}
+
+// defaultResponse is the canned answer to a call; reviews approve
+func defaultResponse(call Call) string {
+	if call.Review {
+		return fmt.Sprintf("fake agent: %s reviewed\nVERDICT: APPROVE", call.TaskID)
+	}
+	return fmt.Sprintf("fake agent: %s done", call.TaskID)
+}
+
+func (a *Agent) match(call Call) *Rule {
+	for i := range a.Rules {
+		rule := &a.Rules[i]
+		if rule.Summary != call.Summary || rule.Review != call.Review {
+			continue
+		}
+		if rule.Title != "" && rule.Title != call.Title {
+			continue
+		}
+		if rule.Match != nil && !rule.Match(call) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// addSubtasks creates subtasks through a separate Hearth instance, like `hearth add` would
+func (a *Agent) addSubtasks(parentID string, subtasks []Subtask) error {
+	h, err := hearth.NewHearth(a.WorkspaceDir)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subtasks {
+		id := h.NextTaskID(&parentID, sub.Title, sub.Description)
+		err := h.Process(&hearth.TaskCreated{
+			TaskID:      id,
+			Title:       sub.Title,
+			Description: sub.Description,
+			ParentID:    &parentID,
+			Verify:      sub.Verify,
+			Agent:       sub.Agent,
+			Model:       sub.Model,
+			Time:        h.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("fake agent: failed to add subtask %q: %w", sub.Title, err)
+		}
+	}
+
+	return nil
+}
+
+// parseCall extracts the task from a Hearth execution or summary prompt
+func parseCall(prompt, workDir string) (Call, error) {
+	if m := executeHeader.FindStringSubmatch(prompt); m != nil {
+		return Call{TaskID: m[2], Title: m[1], Prompt: prompt, WorkDir: workDir}, nil
+	}
+	if m := summaryHeader.FindStringSubmatch(prompt); m != nil {
+		return Call{TaskID: m[2], Title: m[1], Summary: true, Prompt: prompt, WorkDir: workDir}, nil
+	}
+	if m := reviewHeader.FindStringSubmatch(prompt); m != nil {
+		return Call{TaskID: m[2], Title: m[1], Review: true, Prompt: prompt, WorkDir: workDir}, nil
+	}
+	return Call{}, fmt.Errorf("fake agent: no task header in prompt: %q", strings.SplitN(prompt, "\n", 2)[0])
+}
@@ -0,0 +1,47 @@
+package hearthtest
+
+import (
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAgent_MatchesRulesFromPrompts tests that calls are parsed from real Hearth prompts
+func TestAgent_MatchesRulesFromPrompts(t *testing.T) {
+	parentID := "T-1"
+	tasks := map[string]*hearth.Task{
+		"T-1":   {ID: "T-1", Title: "Parent", Status: "in-progress"},
+		"T-1.1": {ID: "T-1.1", Title: "Child", ParentID: &parentID, Status: "completed"},
+	}
+
+	agent := NewAgent(t.TempDir(),
+		Rule{Title: "Child", Response: "child done"},
+		Rule{Title: "Parent", Summary: true, Response: "summary"},
+	)
+
+	prompt, err := hearth.BuildTaskPrompt("T-1.1", tasks, "", hearth.DefaultConfig().Context)
+	assert.NoError(t, err)
+	response, err := agent.Call(prompt, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "child done", response)
+
+	prompt, err = hearth.BuildSummaryPrompt("T-1", tasks)
+	assert.NoError(t, err)
+	response, err = agent.Call(prompt, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "summary", response)
+
+	assert.Equal(t, []string{"Child", "Parent (summary)"}, agent.CalledTitles())
+
+	_, err = agent.Call("not a hearth prompt", "")
+	assert.Error(t, err)
+}
+
+// TestNormalizeTree tests that expected trees can be indented freely
+func TestNormalizeTree(t *testing.T) {
+	assert.Equal(t, "✓ A\n  ○ B\n", normalizeTree(`
+		✓ A
+		  ○ B
+	`))
+}
@@ -0,0 +1,174 @@
+package hearthtest
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+)
+
+// Workspace is a temporary Hearth workspace wired to a fake agent
+type Workspace struct {
+	Dir    string
+	Agent  *Agent
+	Hearth *hearth.Hearth
+
+	t testing.TB
+}
+
+// NewWorkspace creates a workspace in a temp dir using hierarchical task IDs
+// (T-1, T-1.1, ...) and a fake agent following rules
+// Options are applied after the defaults, so they can replace e.g. the config
+func NewWorkspace(t testing.TB, rules []Rule, opts ...hearth.Option) *Workspace {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := hearth.DefaultConfig()
+	cfg.IDs.Strategy = hearth.IDStrategyHierarchical
+	cfg.Retry.Backoff = 0
+
+	// Persist the config so the agent's `hearth add` sees the same ID strategy
+	if err := cfg.Save(dir); err != nil {
+		t.Fatalf("hearthtest: %v", err)
+	}
+
+	agent := NewAgent(dir, rules...)
+	h, err := hearth.New(append([]hearth.Option{
+		hearth.WithWorkspace(dir),
+		hearth.WithConfig(cfg),
+		hearth.WithCaller(agent),
+		hearth.WithOutput(io.Discard),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("hearthtest: %v", err)
+	}
+
+	return &Workspace{Dir: dir, Agent: agent, Hearth: h, t: t}
+}
+
+// AddTask creates a task (root if parentID is empty) and returns its ID
+func (w *Workspace) AddTask(title, description, parentID string) string {
+	w.t.Helper()
+
+	var parent *string
+	if parentID != "" {
+		parent = &parentID
+	}
+
+	id := w.Hearth.NextTaskID(parent, title, description)
+	err := w.Hearth.Process(&hearth.TaskCreated{
+		TaskID:      id,
+		Title:       title,
+		Description: description,
+		ParentID:    parent,
+		Time:        w.Hearth.Now(),
+	})
+	if err != nil {
+		w.t.Fatalf("hearthtest: failed to add task %q: %v", title, err)
+	}
+	return id
+}
+
+// Run executes all eligible tasks, as `hearth run` does
+func (w *Workspace) Run() {
+	w.t.Helper()
+	if err := w.Hearth.Process(&hearth.ExecuteTasksRequested{Time: w.Hearth.Now()}); err != nil {
+		w.t.Fatalf("hearthtest: run failed: %v", err)
+	}
+}
+
+// AssertTree fails the test if the workspace tree differs from expected
+func (w *Workspace) AssertTree(expected string) {
+	w.t.Helper()
+	AssertTree(w.t, w.Hearth, expected)
+}
+
+// AssertTree compares the task tree with expected, one task per line:
+// two spaces of indent per level, a status icon (✓ completed, → in-progress, ✗ failed, ○ todo) and the title.
+// Leading/trailing blank lines and common indentation in expected are ignored.
+func AssertTree(t testing.TB, h *hearth.Hearth, expected string) {
+	t.Helper()
+
+	want := normalizeTree(expected)
+	got := Tree(h)
+	if got != want {
+		t.Errorf("task tree mismatch\n--- want ---\n%s--- got ---\n%s", want, got)
+	}
+}
+
+// Tree renders the task tree in the AssertTree format
+func Tree(h *hearth.Hearth) string {
+	tasks := h.GetTasks()
+
+	var b strings.Builder
+	var walk func(parentID *string, depth int)
+	walk = func(parentID *string, depth int) {
+		for _, task := range childrenOf(tasks, parentID) {
+			b.WriteString(strings.Repeat("  ", depth))
+			b.WriteString(statusIcon(task.Status))
+			b.WriteString(" ")
+			b.WriteString(task.Title)
+			b.WriteString("\n")
+			id := task.ID
+			walk(&id, depth+1)
+		}
+	}
+	walk(nil, 0)
+
+	return b.String()
+}
+
+func childrenOf(tasks map[string]*hearth.Task, parentID *string) []*hearth.Task {
+	var children []*hearth.Task
+	for _, task := range tasks {
+		if (parentID == nil && task.ParentID == nil) ||
+			(parentID != nil && task.ParentID != nil && *task.ParentID == *parentID) {
+			children = append(children, task)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].CreatedBefore(children[j])
+	})
+	return children
+}
+
+func statusIcon(status string) string {
+	switch status {
+	case "completed":
+		return "✓"
+	case "in-progress":
+		return "→"
+	case "failed":
+		return "✗"
+	default:
+		return "○"
+	}
+}
+
+// normalizeTree strips surrounding blank lines and the common indentation of expected trees
+func normalizeTree(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		b.WriteString(strings.TrimRight(line[common:], " \t"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package hearth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// HistoryPoint selects a position in the event log for time-travel queries:
+// right after the event with sequence number Seq, or the last event at or before Time
+// The zero value is the present
+type HistoryPoint struct {
+	Seq  int64
+	Time time.Time
+}
+
+// AtSeq is the point right after the event with the given sequence number
+func AtSeq(seq int64) HistoryPoint { return HistoryPoint{Seq: seq} }
+
+// AtTime is the point in time t (events timestamped later are left out)
+func AtTime(t time.Time) HistoryPoint { return HistoryPoint{Time: t} }
+
+// IsPresent reports whether the point is the current end of the log
+func (p HistoryPoint) IsPresent() bool {
+	return p.Seq == 0 && p.Time.IsZero()
+}
+
+func (p HistoryPoint) String() string {
+	switch {
+	case p.Seq > 0:
+		return fmt.Sprintf("#%d", p.Seq)
+	case !p.Time.IsZero():
+		return p.Time.Format(time.RFC3339)
+	}
+	return "now"
+}
+
+// ParseHistoryPoint parses an event sequence number ("42" or "#42") or a timestamp
+// (RFC 3339, "YYYY-MM-DD HH:MM:SS" or "YYYY-MM-DD", in local time)
+func ParseHistoryPoint(value string) (HistoryPoint, error) {
+	if seq, err := strconv.ParseInt(strings.TrimPrefix(value, "#"), 10, 64); err == nil {
+		if seq < 1 {
+			return HistoryPoint{}, fmt.Errorf("invalid event sequence %d: events are numbered from 1", seq)
+		}
+		return AtSeq(seq), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return AtTime(t), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return AtTime(t), nil
+		}
+	}
+	return HistoryPoint{}, fmt.Errorf("invalid point in history %q: expected an event sequence number or a timestamp", value)
+}
+
+// includes reports whether an event happened at or before the point
+func (p HistoryPoint) includes(record EventRecord) bool {
+	switch {
+	case p.Seq > 0:
+		return record.Seq <= p.Seq
+	case !p.Time.IsZero():
+		return !record.Time.After(p.Time)
+	}
+	return true
+}
+
+// EventsAt returns the prefix of the event log up to the point
+func (h *Hearth) EventsAt(at HistoryPoint) []atmos.Event {
+	events := h.engine.GetEvents()
+	for i, event := range events {
+		if !at.includes(NewEventRecord(event)) {
+			return events[:i]
+		}
+	}
+	return events
+}
+
+// StateAt rebuilds the workspace state as it was at a point in history
+func (h *Hearth) StateAt(at HistoryPoint) (HearthState, error) {
+	if at.Seq > 0 {
+		if last := lastSequence(h.engine.GetEvents()); at.Seq > last {
+			return HearthState{}, fmt.Errorf("event #%d does not exist (the log ends at #%d)", at.Seq, last)
+		}
+	}
+	return replayState(h.EventsAt(at)), nil
+}
+
+// replayState rebuilds the state from a prefix of the event log
+// Only the reducers run, so nothing is executed
+func replayState(events []atmos.Event) HearthState {
+	state := newHearthState()
+	for _, event := range events {
+		state = foldEvent(state, event)
+	}
+	return state
+}
+
+// lastSequence returns the sequence number of the newest event (0 for an empty log)
+func lastSequence(events []atmos.Event) int64 {
+	if len(events) == 0 {
+		return 0
+	}
+	return NewEventRecord(events[len(events)-1]).Seq
+}
@@ -0,0 +1,79 @@
+package hearth
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStateAt tests that the state is rebuilt from a prefix of the log by sequence or time
+func TestStateAt(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	h, err := New(WithWorkspace(t.TempDir()), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+
+	parent := "A"
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "A", Title: "A", Time: start}))                                     // #1
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "B", Title: "B", ParentID: &parent, Time: start.Add(time.Minute)})) // #2
+	assert.NoError(t, h.Process(&TaskCompleted{TaskID: "B", Time: start.Add(2 * time.Minute)}))                          // #3 (+ A completed)
+	assert.Equal(t, "completed", h.GetTask("A").Status)
+
+	state, err := h.StateAt(AtSeq(1))
+	assert.NoError(t, err)
+	assert.Len(t, state.Tasks, 1)
+	assert.Equal(t, "todo", state.Tasks["A"].Status)
+
+	state, err = h.StateAt(AtTime(start.Add(90 * time.Second)))
+	assert.NoError(t, err)
+	assert.Len(t, state.Tasks, 2)
+	assert.Equal(t, "todo", state.Tasks["B"].Status)
+
+	state, err = h.StateAt(AtSeq(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", state.Tasks["B"].Status)
+	assert.Equal(t, "todo", state.Tasks["A"].Status)
+
+	state, err = h.StateAt(AtTime(start.Add(-time.Second)))
+	assert.NoError(t, err)
+	assert.Empty(t, state.Tasks)
+
+	_, err = h.StateAt(AtSeq(99))
+	assert.Error(t, err)
+
+	// Historical state doesn't disturb the present
+	assert.Equal(t, "completed", h.GetTask("A").Status)
+
+	details, err := h.TaskDetailsAt("B", AtSeq(2))
+	assert.NoError(t, err)
+	assert.Equal(t, "todo", details.Task.Status)
+	assert.Len(t, details.Events, 1)
+
+	_, err = h.TaskDetailsAt("B", AtSeq(1))
+	assert.ErrorContains(t, err, "task not found at #1")
+}
+
+// TestParseHistoryPoint tests parsing of sequence numbers and timestamps
+func TestParseHistoryPoint(t *testing.T) {
+	p, err := ParseHistoryPoint("42")
+	assert.NoError(t, err)
+	assert.Equal(t, AtSeq(42), p)
+
+	p, err = ParseHistoryPoint("#7")
+	assert.NoError(t, err)
+	assert.Equal(t, AtSeq(7), p)
+
+	p, err = ParseHistoryPoint("2025-01-02T03:04:05Z")
+	assert.NoError(t, err)
+	assert.Equal(t, AtTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)), p)
+
+	p, err = ParseHistoryPoint("2025-01-02")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.Local), p.Time)
+
+	_, err = ParseHistoryPoint("0")
+	assert.Error(t, err)
+	_, err = ParseHistoryPoint("yesterday")
+	assert.Error(t, err)
+}
@@ -2,10 +2,12 @@ package hearth
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cumulusrpg/atmos"
-	"github.com/fmizzell/hearth/prompts"
 )
 
 // ============================================================================
@@ -22,8 +24,9 @@ func beforeNextTaskSelected(engine *atmos.Engine, event *NextTaskSelected) {
 		tasks = append(tasks, task)
 	}
 
-	// Use existing depth-first logic
-	nextTask := findNextTask(tasks)
+	// Ask the configured scheduler (depth-first by default)
+	nextTask := getScheduler(engine).Next(tasks)
+	logScopeWaits(engine, state.Tasks, nextTask)
 
 	if nextTask == nil {
 		// No tasks available - signal halt
@@ -34,19 +37,41 @@ func beforeNextTaskSelected(engine *atmos.Engine, event *NextTaskSelected) {
 
 	// Task found
 	event.TaskID = nextTask.ID
-	event.Reason = "depth-first-next"
+	event.Reason = "scheduler-next"
 
 	// Log task selection
-	fmt.Printf("📋 Working on %s\n", nextTask.ID)
-	fmt.Printf("   Title: %s\n", nextTask.Title)
-	if nextTask.Description != "" {
-		desc := nextTask.Description
-		if len(desc) > 100 {
-			desc = desc[:100] + "..."
+	getLogger(engine).Info(MsgTaskSelected,
+		"task_id", nextTask.ID,
+		"title", nextTask.Title,
+		"description", truncate(nextTask.Description, 100),
+	)
+}
+
+// logScopeWaits warns about tasks held back by an overlapping scope that would otherwise
+// run before next (nil when nothing was selected), naming the tasks holding the scope
+// A task left in progress by an interrupted run holds its scope until it is reopened
+func logScopeWaits(engine *atmos.Engine, tasks map[string]*Task, next *Task) {
+	for _, task := range sortedTasks(tasks) {
+		if next != nil && !task.CreatedBefore(next) {
+			return
+		}
+		if task.Status != "todo" || hasChildren(task.ID, tasks) {
+			continue
 		}
-		fmt.Printf("   Description: %s\n", desc)
+		blockers := scopeBlockers(task, tasks)
+		if len(blockers) == 0 {
+			continue
+		}
+		var ids []string
+		for _, blocker := range blockers {
+			ids = append(ids, blocker.ID)
+		}
+		getLogger(engine).Warn(MsgScopeBlocked,
+			"task_id", task.ID,
+			"held_by", strings.Join(ids, ","),
+			"hint", "if no run is working on them, hearth doctor reports them as stuck and --fix reopens them",
+		)
 	}
-	fmt.Println()
 }
 
 // beforeTaskExecuted handles task execution using real Claude caller
@@ -61,42 +86,262 @@ func beforeTaskExecuted(engine *atmos.Engine, event *TaskExecuted) {
 
 	// Get services from engine
 	workspaceDir := engine.GetService("workspace_dir")
-	claudeCaller := engine.GetService("claude_caller")
 
-	if workspaceDir == nil || claudeCaller == nil {
+	if workspaceDir == nil || engine.GetService("claude_caller") == nil {
 		// Services not registered - can't execute
 		// This happens in tests that don't register services
 		event.ResultPath = fmt.Sprintf(".hearth/results/%s.md", event.TaskID)
 		return
 	}
 
+	logger := getLogger(engine)
+	cfg := getConfig(engine)
+
+	// Pick the agent and model for this task (task choice, inherited, or workspace defaults)
+	selection := SelectAgent(event.TaskID, state.Tasks, cfg, CallKindExecute)
+	event.Agent = selection.Agent
+	event.Model = selection.Model
+	claudeCaller, err := getAgentCaller(engine, selection)
+	if err != nil {
+		logger.Error(MsgTaskFailed, "task_id", event.TaskID, "error", err)
+		event.ResultPath = ""
+		event.Error = err.Error()
+		return
+	}
+
 	// Log execution start
-	fmt.Println("🤖 Calling Claude...")
-	fmt.Println()
+	logger.Info(MsgCallingAgent, "task_id", event.TaskID, "agent", selection.Agent, "model", selection.Model)
+
+	// Every call (including retries) gets its own execution log
+	logging := &LoggingCaller{
+		Caller:       claudeCaller,
+		WorkspaceDir: workspaceDir.(string),
+		TaskID:       event.TaskID,
+		Clock:        getClock(engine),
+	}
 
-	// Execute task: build context, call Claude, store result
-	resultPath, err := ExecuteTask(
+	// Isolated tasks run in their owner's worktree (created on first use)
+	workDir := workspaceDir.(string)
+	owner := WorktreeOwner(event.TaskID, state.Tasks, cfg.Git.Isolation)
+	if owner != "" {
+		baseDir := parentWorkDir(workDir, owner, state.Tasks, cfg.Git.Isolation)
+		if workDir, err = ensureWorktree(workspaceDir.(string), owner, baseDir); err != nil {
+			logger.Error(MsgTaskFailed, "task_id", event.TaskID, "error", err)
+			event.ResultPath = ""
+			event.Error = firstLine(err.Error())
+			return
+		}
+		event.Worktree, _ = filepath.Rel(workspaceDir.(string), WorktreePath(workspaceDir.(string), owner))
+	}
+
+	// Remember where the task started so its changes can be committed and reverted
+	// Isolated worktrees are always committed - that is how they merge back
+	commit := cfg.Git.Checkpoint || owner != ""
+	checkpoint := commit && IsGitRepo(workDir)
+	if commit && !checkpoint {
+		logger.Warn(MsgCheckpointFailed, "task_id", event.TaskID, "error", "workspace is not a git repository")
+	}
+	if checkpoint {
+		if event.BaseCommit, err = GitHead(workDir); err != nil {
+			logger.Warn(MsgCheckpointFailed, "task_id", event.TaskID, "error", err)
+		}
+	}
+
+	// Snapshot the working directory to record which files the agent touches
+	snapshot, snapErr := TakeSnapshot(workDir)
+	if snapErr != nil {
+		logger.Warn(MsgFileTrackingFailed, "task_id", event.TaskID, "error", snapErr)
+	}
+
+	// Execute task: build context, call Claude (with retries), store result
+	resultPath, err := ExecuteTaskIn(
 		event.TaskID,
 		state.Tasks,
 		workspaceDir.(string),
-		claudeCaller.(ClaudeCaller),
+		workDir,
+		&RetryingCaller{Caller: logging, Policy: cfg.Retry},
+		cfg.Context,
 	)
+	event.Attempt = logging.LastAttempt
+	event.LogPath = logging.LastLogPath
+	event.PromptHash = logging.LastPromptHash
+
+	// Failed attempts can leave changes behind too
+	if snapshot != nil {
+		if event.Files, snapErr = snapshot.Changes(); snapErr != nil {
+			logger.Warn(MsgFileTrackingFailed, "task_id", event.TaskID, "error", snapErr)
+		}
+	}
+
+	// Changes outside the declared scope are reported, or fail the attempt
+	if scope := TaskScope(event.TaskID, state.Tasks); len(scope) > 0 {
+		event.OutOfScope = OutOfScope(scope, event.Files)
+		if len(event.OutOfScope) > 0 {
+			logger.Warn(MsgOutOfScope, "task_id", event.TaskID, "files", strings.Join(event.OutOfScope, ","), "scope", strings.Join(scope, ","))
+			if err == nil && cfg.Scope.Enforce == ScopeFail {
+				err = fmt.Errorf("changed files outside scope %s: %s", strings.Join(scope, ", "), strings.Join(event.OutOfScope, ", "))
+			}
+		}
+	}
 
 	if err != nil {
-		// TODO: Handle execution errors properly
-		// For now, set empty result path to prevent crashes
+		// Recorded on the event; onTaskExecuted escalates or fails the task
+		logger.Error(MsgTaskFailed, "task_id", event.TaskID, "error", err)
 		event.ResultPath = ""
+		event.Error = firstLine(err.Error())
 		return
 	}
 
 	event.ResultPath = resultPath
 
 	// Log execution completion
-	fmt.Printf("✓ Task %s executed\n", event.TaskID)
-	if resultPath != "" {
-		fmt.Printf("   Result stored: %s\n", resultPath)
+	logger.Info(MsgTaskExecuted, "task_id", event.TaskID, "result_path", resultPath, "files", len(event.Files))
+
+	// A failed checkpoint leaves the changes uncommitted but does not fail the task
+	if checkpoint {
+		event.Commit, err = CheckpointCommit(workDir, event.TaskID, task.Title, uncommittedChanges(engine, event))
+		if err != nil {
+			logger.Warn(MsgCheckpointFailed, "task_id", event.TaskID, "error", err)
+		} else if event.Commit != "" {
+			logger.Info(MsgCheckpointed, "task_id", event.TaskID, "commit", ShortSHA(event.Commit))
+		}
+	}
+}
+
+// uncommittedChanges returns the files changed by a task's executions since its last
+// checkpoint, this one included, so changes left by its failed attempts are committed with it
+// and nobody else's work is
+func uncommittedChanges(engine *atmos.Engine, event *TaskExecuted) []string {
+	changed := make(map[string]bool)
+	for _, e := range engine.GetEvents() {
+		executed, ok := e.(*TaskExecuted)
+		if !ok || executed.TaskID != event.TaskID {
+			continue
+		}
+		if executed.Commit != "" {
+			clear(changed)
+			continue
+		}
+		for _, file := range executed.Files {
+			changed[file.Path] = true
+		}
+	}
+	for _, file := range event.Files {
+		changed[file.Path] = true
+	}
+	return sortedKeys(changed)
+}
+
+// beforeTaskVerified runs the task's verification command in the workspace
+func beforeTaskVerified(engine *atmos.Engine, event *TaskVerified) {
+	state := engine.GetState("hearth").(HearthState)
+	cfg := getConfig(engine)
+
+	event.Command = VerifyCommand(event.TaskID, state.Tasks, cfg)
+	workspaceDir, ok := engine.GetService("workspace_dir").(string)
+	if event.Command == "" || !ok {
+		return // Nothing to run - counts as passed
+	}
+
+	// Number runs per task from the log event history
+	for _, e := range engine.GetEvents() {
+		if verified, ok := e.(*TaskVerified); ok && verified.TaskID == event.TaskID {
+			event.Attempt = verified.Attempt
+		}
+	}
+	event.Attempt++
+
+	logger := getLogger(engine)
+	logger.Info(MsgVerifying, "task_id", event.TaskID, "command", event.Command)
+
+	workDir := TaskWorkDir(workspaceDir, event.TaskID, state.Tasks, cfg.Git.Isolation)
+	result, err := RunVerification(event.Command, workDir, time.Duration(cfg.Timeout), getClock(engine))
+	event.ExitCode = result.ExitCode
+	if err != nil {
+		// Could not run at all (e.g. timeout) - treat as a failure with the error as output
+		result.Stderr += "\n" + err.Error()
+		if event.ExitCode == 0 {
+			event.ExitCode = -1
+		}
+	}
+
+	logPath, err := writeVerifyLog(workspaceDir, event.TaskID, event.Attempt, event.Command, result)
+	if err != nil {
+		logger.Error(MsgVerifyLogFailed, "task_id", event.TaskID, "error", err)
+	}
+	event.LogPath = logPath
+}
+
+// beforeTaskReviewed asks the reviewer agent to approve the task's result or request changes
+func beforeTaskReviewed(engine *atmos.Engine, event *TaskReviewed) {
+	state := engine.GetState("hearth").(HearthState)
+	task := state.Tasks[event.TaskID]
+	workspaceDir, ok := engine.GetService("workspace_dir").(string)
+	if task == nil || !ok {
+		// Without a workspace nothing was executed, so there is nothing to review
+		event.Verdict = VerdictApprove
+		return
+	}
+
+	cfg := getConfig(engine)
+	logger := getLogger(engine)
+	event.Round = task.Reviews + 1
+
+	selection := SelectAgent(event.TaskID, state.Tasks, cfg, CallKindReview)
+	event.Agent = selection.Agent
+	event.Model = selection.Model
+
+	// A review that cannot run does not approve the task
+	fail := func(err error) {
+		logger.Error(MsgReviewFailed, "task_id", event.TaskID, "error", err)
+		event.Verdict = VerdictNone
+		event.Error = firstLine(err.Error())
+	}
+
+	result, err := os.ReadFile(filepath.Join(workspaceDir, ".hearth", "results", event.TaskID+".md"))
+	if err != nil && !os.IsNotExist(err) {
+		fail(err)
+		return
+	}
+	workDir := TaskWorkDir(workspaceDir, event.TaskID, state.Tasks, cfg.Git.Isolation)
+	prompt, err := BuildReviewPrompt(event.TaskID, state.Tasks, string(result), gitDiff(workDir, task.BaseCommit))
+	if err != nil {
+		fail(err)
+		return
+	}
+	reviewer, err := getAgentCaller(engine, selection)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	logger.Info(MsgReviewing, "task_id", event.TaskID, "round", event.Round, "agent", selection.Agent, "model", selection.Model)
+
+	logging := &LoggingCaller{
+		Caller:       reviewer,
+		WorkspaceDir: workspaceDir,
+		TaskID:       event.TaskID,
+		Clock:        getClock(engine),
+	}
+	response, err := (&RetryingCaller{Caller: logging, Policy: cfg.Retry}).Call(prompt, workDir)
+	event.Attempt = logging.LastAttempt
+	event.LogPath = logging.LastLogPath
+	event.PromptHash = logging.LastPromptHash
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	event.Verdict = ParseVerdict(response)
+	event.Comments = tail(strings.TrimSpace(response), maxFeedbackLength)
+	if event.Verdict == VerdictNone {
+		event.Error = "the reviewer's response has no VERDICT line"
+		logger.Error(MsgReviewFailed, "task_id", event.TaskID, "error", event.Error)
+	}
+	event.ReviewPath, err = appendReview(workspaceDir, event.TaskID, event.Round, event.Verdict, response)
+	if err != nil {
+		logger.Error(MsgReviewFailed, "task_id", event.TaskID, "error", err)
 	}
-	fmt.Println()
 }
 
 // beforeSummaryGenerated generates a summary by calling Claude with all child results
@@ -110,41 +355,43 @@ func beforeSummaryGenerated(engine *atmos.Engine, event *SummaryGenerated) {
 
 	// Get services
 	workspaceDir := engine.GetService("workspace_dir")
-	claudeCaller := engine.GetService("claude_caller")
 
-	if workspaceDir == nil || claudeCaller == nil {
+	if workspaceDir == nil || engine.GetService("claude_caller") == nil {
 		event.SummaryPath = "" // Can't generate summary without services
 		return
 	}
 
-	// Build enriched prompt with child result references
-	var childrenContext strings.Builder
-	childrenContext.WriteString("\n\nYour subtasks have completed. Here are the results:\n\n")
-
-	for _, t := range state.Tasks {
-		if t.ParentID != nil && *t.ParentID == event.ParentTaskID && t.Status == "completed" {
-			resultPath := fmt.Sprintf(".hearth/results/%s.md", t.ID)
-			childrenContext.WriteString(fmt.Sprintf("- %s \"%s\" → Result: %s\n", t.ID, t.Title, resultPath))
-		}
+	selection := SelectAgent(event.ParentTaskID, state.Tasks, getConfig(engine), CallKindSummary)
+	event.Agent = selection.Agent
+	event.Model = selection.Model
+	claudeCaller, err := getAgentCaller(engine, selection)
+	if err != nil {
+		getLogger(engine).Error(MsgSummaryFailed, "task_id", event.ParentTaskID, "error", err)
+		return
 	}
-	childrenContext.WriteString("\nPlease read these result files and synthesize them into a final answer for the original task.\n")
 
-	prompt := parent.Description
-	if prompt == "" {
-		prompt = parent.Title
+	// Build enriched prompt with child result references
+	fullPrompt, err := BuildSummaryPrompt(event.ParentTaskID, state.Tasks)
+	if err != nil {
+		getLogger(engine).Error(MsgSummaryFailed, "task_id", event.ParentTaskID, "error", err)
+		return
 	}
 
-	taskContext := fmt.Sprintf(`
-ORIGINAL TASK: %s
-TASK ID: %s
-
-`, parent.Title, parent.ID)
-
-	fullPrompt := taskContext + prompt + childrenContext.String() + "\n" + prompts.TaskSystemInstructions
-
 	// Call Claude to generate summary
-	response, err := claudeCaller.(ClaudeCaller).Call(fullPrompt, workspaceDir.(string))
+	logging := &LoggingCaller{
+		Caller:       claudeCaller,
+		WorkspaceDir: workspaceDir.(string),
+		TaskID:       event.ParentTaskID,
+		Clock:        getClock(engine),
+	}
+	caller := &RetryingCaller{Caller: logging, Policy: getConfig(engine).Retry}
+	workDir := TaskWorkDir(workspaceDir.(string), event.ParentTaskID, state.Tasks, getConfig(engine).Git.Isolation)
+	response, err := caller.Call(fullPrompt, workDir)
+	event.Attempt = logging.LastAttempt
+	event.LogPath = logging.LastLogPath
+	event.PromptHash = logging.LastPromptHash
 	if err != nil {
+		getLogger(engine).Error(MsgSummaryFailed, "task_id", event.ParentTaskID, "error", err)
 		event.SummaryPath = ""
 		return
 	}
@@ -152,9 +399,35 @@ TASK ID: %s
 	// Store summary (overwrites original result)
 	summaryPath, err := StoreTaskResult(workspaceDir.(string), event.ParentTaskID, response)
 	if err != nil {
+		getLogger(engine).Error(MsgSummaryStoreFailed, "task_id", event.ParentTaskID, "error", err)
 		event.SummaryPath = ""
 		return
 	}
 
 	event.SummaryPath = summaryPath
 }
+
+// beforeTaskMerged merges the task's worktree branch into its parent's worktree or the workspace
+func beforeTaskMerged(engine *atmos.Engine, event *TaskMerged) {
+	state := engine.GetState("hearth").(HearthState)
+	task := state.Tasks[event.TaskID]
+	workspaceDir, ok := engine.GetService("workspace_dir").(string)
+	if task == nil || !ok {
+		return
+	}
+
+	isolation := getConfig(engine).Git.Isolation
+	targetDir := parentWorkDir(workspaceDir, event.TaskID, state.Tasks, isolation)
+	event.Branch = WorktreeBranch(event.TaskID)
+	event.Into, _ = filepath.Rel(workspaceDir, targetDir)
+
+	commit, conflicts, err := MergeWorktree(workspaceDir, event.TaskID, targetDir, task.Title)
+	if err != nil {
+		event.Conflicts = conflicts
+		event.Error = firstLine(err.Error())
+		return
+	}
+
+	event.Commit = commit
+	getLogger(engine).Info(MsgMerged, "task_id", event.TaskID, "branch", event.Branch, "into", event.Into)
+}
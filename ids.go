@@ -0,0 +1,127 @@
+package hearth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ID strategies selectable in workspace config
+const (
+	IDStrategyShort        = "short"        // T-1a2b3c4d (random)
+	IDStrategySequential   = "sequential"   // T-1, T-2, T-3
+	IDStrategyHierarchical = "hierarchical" // T-3, T-3.1, T-3.1.2
+	IDStrategyHash         = "hash"         // T-<content hash>
+)
+
+// IDGenerator produces IDs for new tasks
+// tasks holds every task ever created, deleted ones included; implementations must
+// return an ID that is not already present in it
+type IDGenerator interface {
+	NextID(tasks map[string]*Task, parentID *string, title, description string) string
+}
+
+// NewIDGenerator returns the generator for a named strategy
+// An empty strategy selects the default (short random IDs)
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	switch strategy {
+	case "", IDStrategyShort:
+		return &ShortIDGenerator{}, nil
+	case IDStrategySequential:
+		return &SequentialIDGenerator{}, nil
+	case IDStrategyHierarchical:
+		return &HierarchicalIDGenerator{}, nil
+	case IDStrategyHash:
+		return &HashIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy: %s (use short, sequential, hierarchical or hash)", strategy)
+	}
+}
+
+// DeterministicIDs reports whether a strategy generates the same IDs when the same tasks
+// are created again (all but the random default)
+func DeterministicIDs(strategy string) bool {
+	return strategy != "" && strategy != IDStrategyShort
+}
+
+// strategyName names a strategy, spelling out the default
+func strategyName(strategy string) string {
+	if strategy == "" {
+		return IDStrategyShort
+	}
+	return strategy
+}
+
+// ShortIDGenerator generates short random IDs from a UUID prefix
+type ShortIDGenerator struct{}
+
+func (g *ShortIDGenerator) NextID(tasks map[string]*Task, parentID *string, title, description string) string {
+	for {
+		id := "T-" + uuid.New().String()[:8]
+		if tasks[id] == nil {
+			return id
+		}
+	}
+}
+
+// SequentialIDGenerator numbers tasks in creation order across the workspace
+// Deleted tasks are counted too, so their numbers are never reused
+type SequentialIDGenerator struct{}
+
+func (g *SequentialIDGenerator) NextID(tasks map[string]*Task, parentID *string, title, description string) string {
+	return nextFreeID(tasks, "T-", len(tasks)+1)
+}
+
+// HierarchicalIDGenerator derives child IDs from their parent (T-3 → T-3.1 → T-3.1.2)
+type HierarchicalIDGenerator struct{}
+
+func (g *HierarchicalIDGenerator) NextID(tasks map[string]*Task, parentID *string, title, description string) string {
+	if parentID == nil {
+		roots := 0
+		for _, t := range tasks {
+			if t.ParentID == nil {
+				roots++
+			}
+		}
+		return nextFreeID(tasks, "T-", roots+1)
+	}
+
+	children := 0
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID == *parentID {
+			children++
+		}
+	}
+	return nextFreeID(tasks, *parentID+".", children+1)
+}
+
+// HashIDGenerator derives IDs from the task content so identical tasks get identical IDs
+// A numeric suffix is appended when the hash is already taken
+type HashIDGenerator struct{}
+
+func (g *HashIDGenerator) NextID(tasks map[string]*Task, parentID *string, title, description string) string {
+	parent := ""
+	if parentID != nil {
+		parent = *parentID
+	}
+
+	sum := sha256.Sum256([]byte(parent + "\x00" + title + "\x00" + description))
+	id := "T-" + hex.EncodeToString(sum[:])[:8]
+	if tasks[id] == nil {
+		return id
+	}
+	return nextFreeID(tasks, id+"-", 2)
+}
+
+// nextFreeID returns prefix+n for the first n (starting at start) not already used
+func nextFreeID(tasks map[string]*Task, prefix string, start int) string {
+	for n := start; ; n++ {
+		id := prefix + strconv.Itoa(n)
+		if tasks[id] == nil {
+			return id
+		}
+	}
+}
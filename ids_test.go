@@ -0,0 +1,112 @@
+package hearth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIDGenerator_Sequential tests that sequential IDs skip IDs already taken
+func TestIDGenerator_Sequential(t *testing.T) {
+	gen, err := NewIDGenerator(IDStrategySequential)
+	assert.NoError(t, err)
+
+	tasks := map[string]*Task{}
+	assert.Equal(t, "T-1", gen.NextID(tasks, nil, "First", ""))
+
+	tasks["T-1"] = &Task{ID: "T-1"}
+	tasks["T-2"] = &Task{ID: "T-2"}
+	tasks["T-4"] = &Task{ID: "T-4"}
+	assert.Equal(t, "T-5", gen.NextID(tasks, nil, "Next", ""))
+
+	delete(tasks, "T-4")
+	tasks["T-3"] = &Task{ID: "T-3"}
+	assert.Equal(t, "T-4", gen.NextID(tasks, nil, "Next", ""))
+}
+
+// TestIDGenerator_Hierarchical tests that child IDs extend their parent's ID
+func TestIDGenerator_Hierarchical(t *testing.T) {
+	gen, err := NewIDGenerator(IDStrategyHierarchical)
+	assert.NoError(t, err)
+
+	tasks := map[string]*Task{
+		"T-1": {ID: "T-1"},
+		"T-2": {ID: "T-2"},
+	}
+	assert.Equal(t, "T-3", gen.NextID(tasks, nil, "Root", ""))
+	assert.Equal(t, "T-2.1", gen.NextID(tasks, strPtr("T-2"), "Child", ""))
+
+	tasks["T-2.1"] = &Task{ID: "T-2.1", ParentID: strPtr("T-2")}
+	assert.Equal(t, "T-2.2", gen.NextID(tasks, strPtr("T-2"), "Child", ""))
+	assert.Equal(t, "T-2.1.1", gen.NextID(tasks, strPtr("T-2.1"), "Grandchild", ""))
+}
+
+// TestIDGenerator_Hash tests that hash IDs are stable and de-duplicated
+func TestIDGenerator_Hash(t *testing.T) {
+	gen, err := NewIDGenerator(IDStrategyHash)
+	assert.NoError(t, err)
+
+	tasks := map[string]*Task{}
+	id := gen.NextID(tasks, nil, "Same", "content")
+	assert.Equal(t, id, gen.NextID(tasks, nil, "Same", "content"))
+	assert.NotEqual(t, id, gen.NextID(tasks, nil, "Other", "content"))
+
+	tasks[id] = &Task{ID: id}
+	assert.Equal(t, id+"-2", gen.NextID(tasks, nil, "Same", "content"))
+}
+
+// TestIDGenerator_UnknownStrategy tests that unknown strategies are rejected
+func TestIDGenerator_UnknownStrategy(t *testing.T) {
+	_, err := NewIDGenerator("uuid7")
+	assert.Error(t, err)
+}
+
+// TestNextTaskID_WorkspaceConfig tests that the ID strategy is read from .hearth/config.yaml
+func TestNextTaskID_WorkspaceConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".hearth"), 0755))
+	assert.NoError(t, os.WriteFile(ConfigPath(tmpDir), []byte("ids:\n  strategy: hierarchical\n"), 0644))
+
+	h, err := NewHearth(tmpDir)
+	assert.NoError(t, err)
+
+	rootID := h.NextTaskID(nil, "Root", "")
+	assert.Equal(t, "T-1", rootID)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: rootID, Title: "Root", Time: time.Now()}))
+
+	childID := h.NextTaskID(strPtr(rootID), "Child", "")
+	assert.Equal(t, "T-1.1", childID)
+}
+
+// TestNextTaskID_NeverReusesDeletedIDs tests that undoing a creation does not free its ID,
+// so the deleted task's events never mix with a new task's
+func TestNextTaskID_NeverReusesDeletedIDs(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.IDs.Strategy = IDStrategySequential
+	h, err := New(WithConfig(cfg))
+	assert.NoError(t, err)
+
+	for _, title := range []string{"One", "Two", "Three", "Four"} {
+		assert.NoError(t, h.Process(&TaskCreated{TaskID: h.NextTaskID(nil, title, ""), Title: title, Time: time.Now()}))
+	}
+	plan, err := h.PlanUndoLast(1)
+	assert.NoError(t, err)
+	assert.NoError(t, h.Undo(plan))
+	assert.Nil(t, h.GetTask("T-4"))
+
+	assert.Equal(t, "T-5", h.NextTaskID(nil, "Five", ""))
+}
+
+// TestTaskCreated_RejectsDuplicateID tests the collision check on task creation
+func TestTaskCreated_RejectsDuplicateID(t *testing.T) {
+	h, err := NewHearth("")
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "First", Time: time.Now()}))
+	err = h.Process(&TaskCreated{TaskID: "T-1", Title: "Duplicate", Time: time.Now()})
+	assert.ErrorIs(t, err, ErrEventRejected)
+	assert.Equal(t, "First", h.GetTask("T-1").Title)
+}
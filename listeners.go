@@ -2,7 +2,7 @@ package hearth
 
 import (
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/cumulusrpg/atmos"
 )
@@ -25,7 +25,7 @@ func (f TypedListenerFunc[T]) HandleTyped(engine *atmos.Engine, event T) {
 
 func onExecuteTasksRequested(engine *atmos.Engine, event *ExecuteTasksRequested) {
 	// Trigger scheduler to find next task
-	engine.Emit(&NextTaskSelected{Time: time.Now()})
+	engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
 }
 
 func onNextTaskSelected(engine *atmos.Engine, event *NextTaskSelected) {
@@ -37,7 +37,7 @@ func onNextTaskSelected(engine *atmos.Engine, event *NextTaskSelected) {
 	// Task was selected, execute it
 	engine.Emit(&TaskExecuted{
 		TaskID: event.TaskID,
-		Time:   time.Now(),
+		Time:   getClock(engine).Now(),
 	})
 }
 
@@ -49,6 +49,12 @@ func onTaskExecuted(engine *atmos.Engine, event *TaskExecuted) {
 		return
 	}
 
+	// Failed call - climb the escalation ladder or give up on the task
+	if event.Error != "" {
+		escalateOrFail(engine, task, event.Error)
+		return
+	}
+
 	// Check if task has children
 	hasChildren := false
 	for _, t := range state.Tasks {
@@ -60,17 +66,221 @@ func onTaskExecuted(engine *atmos.Engine, event *TaskExecuted) {
 
 	if hasChildren {
 		// Has children - don't complete yet, go depth-first into children
-		engine.Emit(&NextTaskSelected{Time: time.Now()})
+		engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+		return
+	}
+
+	// No children - verify first when the task has a verification command
+	if VerifyCommand(event.TaskID, state.Tasks, getConfig(engine)) != "" {
+		engine.Emit(&TaskVerified{
+			TaskID: event.TaskID,
+			Time:   getClock(engine).Now(),
+		})
+		return
+	}
+
+	reviewOrComplete(engine, event.TaskID)
+}
+
+// reviewOrComplete sends a verified task to the reviewer when review is enabled, else completes it
+func reviewOrComplete(engine *atmos.Engine, taskID string) {
+	state := engine.GetState("hearth").(HearthState)
+	if ReviewEnabled(taskID, state.Tasks, getConfig(engine)) {
+		engine.Emit(&TaskReviewed{
+			TaskID: taskID,
+			Time:   getClock(engine).Now(),
+		})
+		return
+	}
+
+	completeTask(engine, taskID)
+}
+
+// completeTask completes a task, merging its worktree back first when it owns one
+func completeTask(engine *atmos.Engine, taskID string) {
+	if needsMerge(engine, taskID) {
+		engine.Emit(&TaskMerged{
+			TaskID: taskID,
+			Time:   getClock(engine).Now(),
+		})
+		return
+	}
+
+	engine.Emit(&TaskCompleted{
+		TaskID: taskID,
+		Time:   getClock(engine).Now(),
+	})
+}
+
+// needsMerge reports whether the task owns a worktree that must be merged before it completes
+func needsMerge(engine *atmos.Engine, taskID string) bool {
+	workspaceDir, ok := engine.GetService("workspace_dir").(string)
+	if !ok {
+		return false
+	}
+	state := engine.GetState("hearth").(HearthState)
+	return ownsWorktree(workspaceDir, taskID, state.Tasks, getConfig(engine).Git.Isolation)
+}
+
+// onTaskMerged completes a merged task; a failed merge becomes a MergeConflict
+func onTaskMerged(engine *atmos.Engine, event *TaskMerged) {
+	if event.Error != "" {
+		engine.Emit(&MergeConflict{
+			TaskID: event.TaskID,
+			Branch: event.Branch,
+			Files:  event.Conflicts,
+			Error:  event.Error,
+			Time:   getClock(engine).Now(),
+		})
 		return
 	}
 
-	// No children - complete the task
 	engine.Emit(&TaskCompleted{
 		TaskID: event.TaskID,
-		Time:   time.Now(),
+		Time:   getClock(engine).Now(),
 	})
 }
 
+// onMergeConflict moves on to the next task; the failed task's ancestors stay open
+func onMergeConflict(engine *atmos.Engine, event *MergeConflict) {
+	getLogger(engine).Error(MsgMergeConflict, "task_id", event.TaskID, "branch", event.Branch, "files", strings.Join(event.Files, ","), "error", event.Error)
+
+	if finishesRepair(engine, event.TaskID) {
+		return
+	}
+	engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+}
+
+// onTaskReviewed completes approved tasks; requested changes re-queue the task until review.max_rounds
+func onTaskReviewed(engine *atmos.Engine, event *TaskReviewed) {
+	logger := getLogger(engine)
+	logger.Info(MsgReviewed, "task_id", event.TaskID, "round", event.Round, "verdict", event.Verdict)
+
+	// A broken reviewer must not wave tasks through; escalating would re-run the executor,
+	// which cannot fix the reviewer, so the task fails with the review's error
+	if event.Verdict == VerdictNone {
+		engine.Emit(&TaskFailed{
+			TaskID: event.TaskID,
+			Error:  "review failed: " + event.Error,
+			Time:   getClock(engine).Now(),
+		})
+		return
+	}
+
+	if event.Verdict == VerdictRequestChanges {
+		state := engine.GetState("hearth").(HearthState)
+		maxRounds := getConfig(engine).Review.MaxRounds
+		if task := state.Tasks[event.TaskID]; task != nil && task.Reviews <= maxRounds {
+			// Re-queued as todo with the review as feedback - the scheduler picks it up again
+			engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+			return
+		}
+		logger.Warn(MsgReviewRoundsExhausted, "task_id", event.TaskID, "max_rounds", maxRounds)
+	}
+
+	completeTask(engine, event.TaskID)
+}
+
+// onTaskVerified completes the task when verification passed, otherwise re-queues it
+func onTaskVerified(engine *atmos.Engine, event *TaskVerified) {
+	if event.ExitCode == 0 {
+		getLogger(engine).Info(MsgVerifyPassed, "task_id", event.TaskID)
+		reviewOrComplete(engine, event.TaskID)
+		return
+	}
+
+	// The reducer adds the command and exit code when it builds the task's feedback
+	output := ""
+	if event.LogPath != "" {
+		if data, err := readVerifyOutput(event.LogPath, event.Command, event.ExitCode); err == nil {
+			output = tail(data, maxFeedbackLength)
+		}
+	}
+
+	engine.Emit(&VerificationFailed{
+		TaskID:   event.TaskID,
+		Command:  event.Command,
+		ExitCode: event.ExitCode,
+		Output:   output,
+		Time:     getClock(engine).Now(),
+	})
+}
+
+// onVerificationFailed retries the task until verify.max_attempts, then escalates or fails it
+func onVerificationFailed(engine *atmos.Engine, event *VerificationFailed) {
+	state := engine.GetState("hearth").(HearthState)
+	task := state.Tasks[event.TaskID]
+	if task == nil {
+		return
+	}
+
+	cfg := getConfig(engine)
+	getLogger(engine).Warn(MsgVerifyFailed,
+		"task_id", event.TaskID,
+		"exit_code", event.ExitCode,
+		"failures", task.Failures,
+		"max_attempts", cfg.Verify.MaxAttempts,
+	)
+
+	if task.Failures >= cfg.Verify.MaxAttempts {
+		escalateOrFail(engine, task, fmt.Sprintf("verification failed: %s (exit code %d)", event.Command, event.ExitCode))
+		return
+	}
+
+	// Re-queued as todo - the scheduler picks it up again
+	engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+}
+
+// escalateOrFail retries a failed task on the next escalation rung, or fails it when none is left
+func escalateOrFail(engine *atmos.Engine, task *Task, reason string) {
+	cfg := getConfig(engine)
+
+	rung := task.Rung + 1
+	if rung > len(cfg.Escalation) {
+		engine.Emit(&TaskFailed{
+			TaskID: task.ID,
+			Error:  reason,
+			Time:   getClock(engine).Now(),
+		})
+		return
+	}
+
+	state := engine.GetState("hearth").(HearthState)
+	selection := selectAgent(task.ID, state.Tasks, cfg, CallKindExecute, rung)
+
+	engine.Emit(&TaskEscalated{
+		TaskID: task.ID,
+		Rung:   rung,
+		Agent:  selection.Agent,
+		Model:  selection.Model,
+		Reason: reason,
+		Time:   getClock(engine).Now(),
+	})
+}
+
+// onTaskEscalated runs the task again on its new rung
+func onTaskEscalated(engine *atmos.Engine, event *TaskEscalated) {
+	getLogger(engine).Warn(MsgTaskEscalated,
+		"task_id", event.TaskID,
+		"rung", event.Rung,
+		"agent", event.Agent,
+		"model", event.Model,
+		"reason", event.Reason,
+	)
+
+	engine.Emit(&TaskExecuted{
+		TaskID: event.TaskID,
+		Time:   getClock(engine).Now(),
+	})
+}
+
+// onTaskFailed moves on to the next task; the failed task's ancestors stay open
+func onTaskFailed(engine *atmos.Engine, event *TaskFailed) {
+	getLogger(engine).Error(MsgTaskGaveUp, "task_id", event.TaskID, "error", event.Error)
+
+	engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+}
+
 // onTaskCompletedParent handles parent auto-completion
 // This replaces the autoCompleteParent mutation in the reducer
 func onTaskCompletedParent(engine *atmos.Engine, event *TaskCompleted) {
@@ -98,11 +308,9 @@ func onTaskCompletedParent(engine *atmos.Engine, event *TaskCompleted) {
 				childCount++
 			}
 		}
-		fmt.Printf("✓ Task %s spawned %d subtasks (will auto-complete when subtasks finish)\n", event.TaskID, childCount)
-		fmt.Println()
+		getLogger(engine).Info(MsgTaskSpawned, "task_id", event.TaskID, "subtasks", childCount)
 	} else {
-		fmt.Printf("✓ Task %s completed\n", event.TaskID)
-		fmt.Println()
+		getLogger(engine).Info(MsgTaskCompleted, "task_id", event.TaskID)
 	}
 
 	// Check if parent should be completed
@@ -124,7 +332,8 @@ func onTaskCompletedParent(engine *atmos.Engine, event *TaskCompleted) {
 				// All children done - request summary generation
 				engine.Emit(&SummaryRequested{
 					ParentTaskID: *task.ParentID,
-					Time:         time.Now(),
+					Repair:       finishesRepair(engine, event.TaskID),
+					Time:         getClock(engine).Now(),
 				})
 				// Summary generation will complete the parent
 			}
@@ -144,12 +353,20 @@ func onTaskCompletedSchedule(engine *atmos.Engine, event *TaskCompleted) {
 	// Check if this completion came from orchestration (TaskExecuted before TaskCompleted)
 	// This prevents auto-scheduling when tasks are manually completed
 	lastEvent := events[len(events)-2] // event before this TaskCompleted
-	if lastEvent.Type() == "task_executed" {
-		executed := lastEvent.(*TaskExecuted)
-		if executed.TaskID == event.TaskID {
-			// This task was executed by orchestration - continue scheduling
-			engine.Emit(&NextTaskSelected{Time: time.Now()})
-		}
+	var lastTaskID string
+	switch last := lastEvent.(type) {
+	case *TaskExecuted:
+		lastTaskID = last.TaskID
+	case *TaskVerified:
+		lastTaskID = last.TaskID
+	case *TaskReviewed:
+		lastTaskID = last.TaskID
+	case *TaskMerged:
+		lastTaskID = last.TaskID
+	}
+	if lastTaskID == event.TaskID && !finishesRepair(engine, event.TaskID) {
+		// This task was executed (and verified/reviewed) by orchestration - continue scheduling
+		engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
 	}
 }
 
@@ -157,18 +374,45 @@ func onTaskCompletedSchedule(engine *atmos.Engine, event *TaskCompleted) {
 func onSummaryRequested(engine *atmos.Engine, event *SummaryRequested) {
 	engine.Emit(&SummaryGenerated{
 		ParentTaskID: event.ParentTaskID,
-		Time:         time.Now(),
+		Repair:       event.Repair,
+		Time:         getClock(engine).Now(),
 	})
 }
 
 // onSummaryGenerated completes the parent task after summary is generated
 func onSummaryGenerated(engine *atmos.Engine, event *SummaryGenerated) {
+	// An isolated parent merges first; completion then continues scheduling itself
+	if needsMerge(engine, event.ParentTaskID) {
+		completeTask(engine, event.ParentTaskID)
+		return
+	}
+
 	// Summary complete - now complete the parent task
 	engine.Emit(&TaskCompleted{
 		TaskID: event.ParentTaskID,
-		Time:   time.Now(),
+		Time:   getClock(engine).Now(),
 	})
 
-	// Continue orchestration - find next task
-	engine.Emit(&NextTaskSelected{Time: time.Now()})
+	// Continue orchestration - find next task (a repair only completes the parent)
+	if !event.Repair {
+		engine.Emit(&NextTaskSelected{Time: getClock(engine).Now()})
+	}
+}
+
+// finishesRepair reports whether the task's latest event completes a summary requested
+// by hearth doctor: the repair summary came right before it, possibly followed by the merge
+func finishesRepair(engine *atmos.Engine, taskID string) bool {
+	events := engine.GetEvents()
+	for i := len(events) - 2; i >= 0; i-- {
+		switch e := events[i].(type) {
+		case *TaskMerged:
+			if e.TaskID == taskID {
+				continue
+			}
+		case *SummaryGenerated:
+			return e.ParentTaskID == taskID && e.Repair
+		}
+		return false
+	}
+	return false
 }
@@ -0,0 +1,197 @@
+package hearth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Log messages emitted by the orchestration loop
+// The human handler renders these as the familiar emoji progress lines;
+// other handlers (e.g. JSON) receive them as plain structured records
+const (
+	MsgRunStarted            = "run started"
+	MsgRunFinished           = "run finished"
+	MsgPresetCreated         = "preset task created"
+	MsgExecutionStarted      = "execution started"
+	MsgTaskSelected          = "task selected"
+	MsgScopeBlocked          = "task waiting for an overlapping scope"
+	MsgCallingAgent          = "calling agent"
+	MsgTaskExecuted          = "task executed"
+	MsgTaskSpawned           = "task spawned subtasks"
+	MsgTaskCompleted         = "task completed"
+	MsgTaskFailed            = "task execution failed"
+	MsgTaskEscalated         = "task escalated"
+	MsgVerifying             = "verifying task"
+	MsgVerifyPassed          = "verification passed"
+	MsgVerifyFailed          = "verification failed"
+	MsgVerifyLogFailed       = "failed to store verification output"
+	MsgReviewing             = "reviewing task"
+	MsgReviewed              = "task reviewed"
+	MsgReviewFailed          = "review failed"
+	MsgReviewRoundsExhausted = "review rounds exhausted"
+	MsgTaskGaveUp            = "task failed"
+	MsgCheckpointed          = "task changes committed"
+	MsgCheckpointFailed      = "failed to commit task changes"
+	MsgMerged                = "worktree merged"
+	MsgFileTrackingFailed    = "failed to track changed files"
+	MsgOutOfScope            = "changes outside task scope"
+	MsgMergeConflict         = "worktree merge failed"
+	MsgSummaryFailed         = "summary generation failed"
+	MsgSummaryStoreFailed    = "failed to store summary"
+)
+
+// Log formats accepted by NewLogger
+const (
+	LogFormatHuman = "human"
+	LogFormatJSON  = "json"
+)
+
+// NewLogger creates a logger writing to w in the given format and level
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q (use debug, info, warn or error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch format {
+	case "", LogFormatHuman:
+		return slog.New(NewHumanHandler(w, opts)), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (use human or json)", format)
+	}
+}
+
+// HumanHandler is a slog.Handler that renders orchestration messages for a terminal
+type HumanHandler struct {
+	w     io.Writer
+	mu    *sync.Mutex
+	level slog.Leveler
+	attrs []slog.Attr
+	group string
+}
+
+// NewHumanHandler creates a handler writing human-friendly lines to w
+func NewHumanHandler(w io.Writer, opts *slog.HandlerOptions) *HumanHandler {
+	var level slog.Leveler = slog.LevelInfo
+	if opts != nil && opts.Level != nil {
+		level = opts.Level
+	}
+	return &HumanHandler{w: w, mu: &sync.Mutex{}, level: level}
+}
+
+func (h *HumanHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *HumanHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), h.qualify(attrs)...)
+	return &clone
+}
+
+func (h *HumanHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	if clone.group != "" {
+		name = clone.group + "." + name
+	}
+	clone.group = name
+	return &clone
+}
+
+func (h *HumanHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := append([]slog.Attr{}, h.attrs...)
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.qualify([]slog.Attr{a})...)
+		return true
+	})
+
+	values := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		values[a.Key] = a.Value.Resolve().String()
+	}
+
+	var b strings.Builder
+	switch record.Message {
+	case MsgRunStarted:
+		b.WriteString("🔥 Hearth - Autonomous Task Orchestration\n")
+		fmt.Fprintf(&b, "📂 Workspace: %s\n", values["workspace"])
+		if runID := values["run_id"]; runID != "" {
+			fmt.Fprintf(&b, "🏷  Run: %s\n", runID)
+		}
+		b.WriteString("\n")
+	case MsgRunFinished:
+		b.WriteString("\n✅ All tasks completed!\n🎉 Hearth finished!\n")
+	case MsgPresetCreated:
+		fmt.Fprintf(&b, "✓ Created task %s from preset '%s'\n\n", values["task_id"], values["preset"])
+	case MsgExecutionStarted:
+		b.WriteString("🤖 Starting autonomous task execution...\n\n")
+	case MsgTaskSelected:
+		fmt.Fprintf(&b, "📋 Working on %s\n", values["task_id"])
+		fmt.Fprintf(&b, "   Title: %s\n", values["title"])
+		if desc := values["description"]; desc != "" {
+			fmt.Fprintf(&b, "   Description: %s\n", desc)
+		}
+		b.WriteString("\n")
+	case MsgCallingAgent:
+		switch {
+		case values["agent"] == "":
+			b.WriteString("🤖 Calling agent...\n\n")
+		case values["model"] == "":
+			fmt.Fprintf(&b, "🤖 Calling %s...\n\n", values["agent"])
+		default:
+			fmt.Fprintf(&b, "🤖 Calling %s (%s)...\n\n", values["agent"], values["model"])
+		}
+	case MsgTaskExecuted:
+		fmt.Fprintf(&b, "✓ Task %s executed\n", values["task_id"])
+		if path := values["result_path"]; path != "" {
+			fmt.Fprintf(&b, "   Result stored: %s\n", path)
+		}
+		if files := values["files"]; files != "" && files != "0" {
+			fmt.Fprintf(&b, "   Files changed: %s\n", files)
+		}
+		b.WriteString("\n")
+	case MsgTaskSpawned:
+		fmt.Fprintf(&b, "✓ Task %s spawned %s subtasks (will auto-complete when subtasks finish)\n\n", values["task_id"], values["subtasks"])
+	case MsgTaskCompleted:
+		fmt.Fprintf(&b, "✓ Task %s completed\n\n", values["task_id"])
+	default:
+		// Generic rendering: icon by level, message, then key=value pairs
+		switch {
+		case record.Level >= slog.LevelError:
+			b.WriteString("✗ ")
+		case record.Level >= slog.LevelWarn:
+			b.WriteString("⚠ ")
+		default:
+			b.WriteString("  ")
+		}
+		b.WriteString(record.Message)
+		for _, a := range attrs {
+			fmt.Fprintf(&b, " %s=%s", a.Key, a.Value.Resolve().String())
+		}
+		b.WriteString("\n")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+// qualify prefixes attribute keys with the handler's group
+func (h *HumanHandler) qualify(attrs []slog.Attr) []slog.Attr {
+	if h.group == "" {
+		return attrs
+	}
+	qualified := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		qualified[i] = slog.Attr{Key: h.group + "." + a.Key, Value: a.Value}
+	}
+	return qualified
+}
@@ -0,0 +1,57 @@
+package hearth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHumanHandler_RendersOrchestrationMessages tests the emoji progress output
+func TestHumanHandler_RendersOrchestrationMessages(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHumanHandler(&buf, nil))
+
+	logger.Info(MsgTaskSelected, "task_id", "T-1", "title", "Fix bug", "description", "")
+	logger.Info(MsgTaskSpawned, "task_id", "T-1", "subtasks", 3)
+	logger.Error(MsgTaskFailed, "task_id", "T-1", "error", errors.New("boom"))
+	logger.Debug("hidden below info level")
+
+	assert.Equal(t, "📋 Working on T-1\n   Title: Fix bug\n\n"+
+		"✓ Task T-1 spawned 3 subtasks (will auto-complete when subtasks finish)\n\n"+
+		"✗ task execution failed task_id=T-1 error=boom\n", buf.String())
+}
+
+// TestNewLogger_JSON tests that orchestration output can be machine-readable
+func TestNewLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, LogFormatJSON, "info")
+	assert.NoError(t, err)
+
+	h, err := New(WithCaller(&MockClaudeCaller{}), WithLogger(logger))
+	assert.NoError(t, err)
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "T-1", Title: "Task", Time: time.Now()}))
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: time.Now()}))
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		messages = append(messages, record["msg"].(string))
+		assert.Equal(t, "T-1", record["task_id"])
+	}
+	assert.Equal(t, []string{MsgTaskSelected, MsgTaskCompleted}, messages)
+}
+
+// TestNewLogger_Invalid tests that unknown formats and levels are rejected
+func TestNewLogger_Invalid(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "xml", "info")
+	assert.Error(t, err)
+	_, err = NewLogger(&bytes.Buffer{}, LogFormatHuman, "loud")
+	assert.Error(t, err)
+}
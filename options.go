@@ -0,0 +1,211 @@
+package hearth
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Option configures a Hearth instance created with New
+type Option func(*options)
+
+type options struct {
+	workspaceDir string
+	config       *Config
+	repository   atmos.EventRepository
+	caller       AgentCaller
+	wrapCaller   func(AgentCaller) AgentCaller
+	clock        Clock
+	idGenerator  IDGenerator
+	output       io.Writer
+	scheduler    Scheduler
+	logger       *slog.Logger
+	runID        string
+}
+
+// WithWorkspace sets the workspace directory used for persistence, results and agent calls
+func WithWorkspace(dir string) Option {
+	return func(o *options) { o.workspaceDir = dir }
+}
+
+// WithConfig sets the configuration (defaults to DefaultConfig)
+func WithConfig(cfg *Config) Option {
+	return func(o *options) { o.config = cfg }
+}
+
+// WithRepository sets the event repository (overrides the workspace event file)
+func WithRepository(repo atmos.EventRepository) Option {
+	return func(o *options) { o.repository = repo }
+}
+
+// WithCaller sets the agent caller used for task execution and summaries
+func WithCaller(caller AgentCaller) Option {
+	return func(o *options) { o.caller = caller }
+}
+
+// WithCallerWrapper wraps every agent caller: the default one and those created for
+// per-task, escalation and review agents (e.g. to record a session, see RecordingCaller)
+func WithCallerWrapper(wrap func(AgentCaller) AgentCaller) Option {
+	return func(o *options) { o.wrapCaller = wrap }
+}
+
+// WithClock sets the clock used to timestamp orchestration events
+func WithClock(clock Clock) Option {
+	return func(o *options) { o.clock = clock }
+}
+
+// WithIDGenerator sets the task ID generator (overrides ids.strategy)
+func WithIDGenerator(generator IDGenerator) Option {
+	return func(o *options) { o.idGenerator = generator }
+}
+
+// WithOutput sets where human-friendly progress output is written (defaults to stdout)
+// Ignored when WithLogger is also given
+func WithOutput(w io.Writer) Option {
+	return func(o *options) { o.output = w }
+}
+
+// WithScheduler sets the task scheduler (overrides the scheduler config key)
+func WithScheduler(scheduler Scheduler) Option {
+	return func(o *options) { o.scheduler = scheduler }
+}
+
+// WithLogger sets the logger used for progress output, errors and diagnostics
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithRunID tags every event stored by this instance with a run (see RunIDEnv)
+func WithRunID(runID string) Option {
+	return func(o *options) { o.runID = runID }
+}
+
+// register exposes the options to hooks and listeners through the engine's service locator
+func (o *options) register(engine *atmos.Engine) error {
+	engine.RegisterService("config", o.config)
+	engine.RegisterService("id_generator", o.idGenerator)
+	engine.RegisterService("scheduler", o.scheduler)
+
+	if o.clock != nil {
+		engine.RegisterService("clock", o.clock)
+	}
+	if o.runID != "" {
+		engine.RegisterService("run_id", o.runID)
+	}
+	if o.logger == nil && o.output != nil {
+		o.logger = slog.New(NewHumanHandler(o.output, nil))
+	}
+	if o.logger != nil {
+		engine.RegisterService("logger", o.logger)
+	}
+
+	// Orchestration services: a workspace gets the configured agent backend by default
+	if o.workspaceDir != "" {
+		engine.RegisterService("workspace_dir", o.workspaceDir)
+		if o.caller == nil {
+			caller, err := NewAgentCaller(o.config, "", "")
+			if err != nil {
+				return err
+			}
+			o.caller = o.timed(caller)
+
+			// Configured backends can be chosen per task; an injected caller handles every call
+			cfg := o.config
+			engine.RegisterService("agent_factory", AgentFactory(func(selection AgentSelection) (AgentCaller, error) {
+				caller, err := NewAgentCaller(cfg, selection.Agent, selection.Model)
+				if err != nil {
+					return nil, err
+				}
+				return o.wrap(o.timed(caller)), nil
+			}))
+		}
+	}
+	if o.caller != nil {
+		engine.RegisterService("claude_caller", o.wrap(o.caller))
+	}
+	return nil
+}
+
+// wrap applies the caller wrapper, if any
+func (o *options) wrap(caller AgentCaller) AgentCaller {
+	if o.wrapCaller == nil {
+		return caller
+	}
+	return o.wrapCaller(caller)
+}
+
+// timed makes the command-line backends time their calls with the injected clock, if any
+func (o *options) timed(caller AgentCaller) AgentCaller {
+	switch c := caller.(type) {
+	case *DefaultClaudeCaller:
+		c.Clock = o.clock
+	case *CLIAgent:
+		c.Clock = o.clock
+	}
+	return caller
+}
+
+// ============================================================================
+// SERVICE LOOKUP - Registered services with fallbacks for bare engines
+// ============================================================================
+
+// getConfig returns the registered workspace config (defaults if none registered)
+func getConfig(engine *atmos.Engine) *Config {
+	if cfg, ok := engine.GetService("config").(*Config); ok {
+		return cfg
+	}
+	return DefaultConfig()
+}
+
+// getAgentCaller returns the caller for a selection
+// The workspace default selection uses the registered caller; others go through the
+// agent factory when one is registered (with WithCaller, the caller handles every call)
+func getAgentCaller(engine *atmos.Engine, selection AgentSelection) (AgentCaller, error) {
+	factory, ok := engine.GetService("agent_factory").(AgentFactory)
+	if ok && selection != SelectAgent("", nil, getConfig(engine), "") {
+		return factory(selection)
+	}
+	caller, ok := engine.GetService("claude_caller").(AgentCaller)
+	if !ok {
+		return nil, fmt.Errorf("no agent caller registered")
+	}
+	return caller, nil
+}
+
+func getClock(engine *atmos.Engine) Clock {
+	if clock, ok := engine.GetService("clock").(Clock); ok {
+		return clock
+	}
+	return SystemClock{}
+}
+
+// getRunID returns the run events are tagged with ("" outside a run)
+func getRunID(engine *atmos.Engine) string {
+	runID, _ := engine.GetService("run_id").(string)
+	return runID
+}
+
+func getIDGenerator(engine *atmos.Engine) IDGenerator {
+	if generator, ok := engine.GetService("id_generator").(IDGenerator); ok {
+		return generator
+	}
+	return &ShortIDGenerator{}
+}
+
+func getScheduler(engine *atmos.Engine) Scheduler {
+	if scheduler, ok := engine.GetService("scheduler").(Scheduler); ok {
+		return scheduler
+	}
+	return &DepthFirstScheduler{}
+}
+
+// getLogger returns the registered logger (human-friendly stdout output if none registered)
+func getLogger(engine *atmos.Engine) *slog.Logger {
+	if logger, ok := engine.GetService("logger").(*slog.Logger); ok {
+		return logger
+	}
+	return slog.New(NewHumanHandler(os.Stdout, nil))
+}
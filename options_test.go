@@ -0,0 +1,86 @@
+package hearth
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedClock always returns the same time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+// lastCreatedScheduler picks the most recently created todo task
+type lastCreatedScheduler struct{}
+
+func (s lastCreatedScheduler) Next(tasks []*Task) *Task {
+	var next *Task
+	for _, t := range tasks {
+		if t.Status == "todo" && (next == nil || next.CreatedBefore(t)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// TestNew_Options tests that options replace the defaults NewHearth would choose
+func TestNew_Options(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := atmos.NewInMemoryRepository()
+	clock := fixedClock{now: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+	var out bytes.Buffer
+	mockCaller := &MockClaudeCaller{}
+
+	h, err := New(
+		WithWorkspace(tmpDir),
+		WithRepository(repo),
+		WithCaller(mockCaller),
+		WithClock(clock),
+		WithIDGenerator(&SequentialIDGenerator{}),
+		WithOutput(&out),
+		WithScheduler(lastCreatedScheduler{}),
+	)
+	assert.NoError(t, err)
+
+	// ID generator
+	assert.Equal(t, "T-1", h.NextTaskID(nil, "First", ""))
+
+	now := time.Now()
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "OLD", Title: "Old", Time: now}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "NEW", Title: "New", Time: now.Add(time.Second)}))
+
+	// Scheduler
+	assert.Equal(t, "NEW", h.GetNextTask().ID)
+
+	// Repository: events went to the in-memory repository, not the workspace file
+	assert.Len(t, repo.GetAll(h.Engine()), 2)
+	assert.NoFileExists(t, tmpDir+"/.hearth/events.json")
+
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: now}))
+
+	// Caller and output
+	assert.Equal(t, 2, mockCaller.CallCount)
+	assert.Contains(t, out.String(), "📋 Working on NEW")
+
+	// Clock: orchestration events are stamped by the injected clock
+	for _, e := range h.Engine().GetEvents() {
+		if selected, ok := e.(*NextTaskSelected); ok {
+			assert.Equal(t, clock.now, selected.Time)
+		}
+	}
+}
+
+// TestNew_InMemoryDefaults tests that New without options behaves like NewHearth("")
+func TestNew_InMemoryDefaults(t *testing.T) {
+	h, err := New()
+	assert.NoError(t, err)
+	assert.Nil(t, h.Engine().GetService("workspace_dir"))
+	assert.Nil(t, h.Engine().GetService("claude_caller"))
+	assert.Equal(t, getConfig(h.Engine()), h.Engine().GetService("config"))
+}
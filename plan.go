@@ -0,0 +1,101 @@
+package hearth
+
+import "fmt"
+
+// Plan step kinds
+const (
+	StepExecute = "execute"
+	StepSummary = "summary"
+)
+
+// PlanStep is one agent call a run would make
+type PlanStep struct {
+	Kind   string // StepExecute or StepSummary
+	Task   *Task  // snapshot of the task at planning time
+	Depth  int    // distance from the root task
+	Prompt string // prompt that would be sent
+}
+
+// Plan returns the agent calls a run would make from the current state, in order
+// Nothing is executed or persisted - see PlanRun
+func (h *Hearth) Plan() ([]PlanStep, error) {
+	workspaceDir, _ := h.engine.GetService("workspace_dir").(string)
+	return PlanRun(h.GetTasks(), getScheduler(h.engine), workspaceDir, h.Config().Context)
+}
+
+// PlanRun simulates orchestration on a copy of the tasks: each scheduled task is
+// assumed to complete without spawning subtasks, and parents get a summary step
+// once all their children are done. The input tasks are not modified.
+func PlanRun(tasks map[string]*Task, scheduler Scheduler, workspaceDir string, policy ContextConfig) ([]PlanStep, error) {
+	sim := cloneTasks(tasks)
+
+	var steps []PlanStep
+	for {
+		var slice []*Task
+		for _, t := range sim {
+			slice = append(slice, t)
+		}
+
+		next := scheduler.Next(slice)
+		if next == nil {
+			return steps, nil
+		}
+		if next.Status == "completed" {
+			return nil, fmt.Errorf("scheduler selected completed task %s", next.ID)
+		}
+
+		prompt, err := BuildTaskPrompt(next.ID, sim, workspaceDir, policy)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, PlanStep{Kind: StepExecute, Task: snapshot(next), Depth: taskDepth(next, sim), Prompt: prompt})
+		next.Status = "completed"
+
+		// Parents whose children are now all done get summarized, up the chain
+		for parent := parentOf(next, sim); parent != nil && allChildrenCompleted(parent.ID, sim); parent = parentOf(parent, sim) {
+			if parent.Status == "completed" {
+				break
+			}
+			prompt, err := BuildSummaryPrompt(parent.ID, sim)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, PlanStep{Kind: StepSummary, Task: snapshot(parent), Depth: taskDepth(parent, sim), Prompt: prompt})
+			parent.Status = "completed"
+		}
+	}
+}
+
+// cloneTasks copies every task so the copy can be mutated freely
+func cloneTasks(tasks map[string]*Task) map[string]*Task {
+	clone := make(map[string]*Task, len(tasks))
+	for id, t := range tasks {
+		clone[id] = snapshot(t)
+	}
+	return clone
+}
+
+func snapshot(t *Task) *Task {
+	c := *t
+	return &c
+}
+
+func parentOf(task *Task, tasks map[string]*Task) *Task {
+	if task.ParentID == nil {
+		return nil
+	}
+	return tasks[*task.ParentID]
+}
+
+func taskDepth(task *Task, tasks map[string]*Task) int {
+	return len(buildParentChain(task, tasks))
+}
+
+func allChildrenCompleted(parentID string, tasks map[string]*Task) bool {
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID == parentID && t.Status != "completed" {
+			return false
+		}
+	}
+	return true
+}
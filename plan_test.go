@@ -0,0 +1,46 @@
+package hearth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlan_DepthFirstWithSummaries tests that the plan mirrors orchestration order
+func TestPlan_DepthFirstWithSummaries(t *testing.T) {
+	h, err := NewHearth("")
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "ROOT", Title: "Root", Time: now}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "A", Title: "A", ParentID: strPtr("ROOT"), Time: now.Add(1 * time.Second)}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "A1", Title: "A1", ParentID: strPtr("A"), Time: now.Add(2 * time.Second)}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "B", Title: "B", ParentID: strPtr("ROOT"), Time: now.Add(3 * time.Second)}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "OTHER", Title: "Other root", Time: now.Add(4 * time.Second)}))
+
+	steps, err := h.Plan()
+	assert.NoError(t, err)
+
+	var order []string
+	for _, step := range steps {
+		order = append(order, step.Kind+":"+step.Task.ID)
+	}
+	assert.Equal(t, []string{
+		"execute:A1",
+		"summary:A",
+		"execute:B",
+		"summary:ROOT",
+		"execute:OTHER",
+	}, order)
+
+	assert.Equal(t, 2, steps[0].Depth)
+	assert.Contains(t, steps[0].Prompt, "CURRENT TASK ID: A1")
+	// B sees A's (simulated) result as a completed sibling
+	assert.Contains(t, steps[2].Prompt, "- A \"A\" → Result: .hearth/results/A.md")
+	assert.Contains(t, steps[3].Prompt, "ORIGINAL TASK: Root")
+
+	// Nothing changed in the real state or event log
+	assert.Equal(t, "todo", h.GetTask("A1").Status)
+	assert.Len(t, h.Engine().GetEvents(), 5)
+}
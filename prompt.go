@@ -0,0 +1,141 @@
+package hearth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fmizzell/hearth/prompts"
+)
+
+// BuildTaskPrompt renders the full prompt sent when executing a task:
+// injected context, the task header, its description and the system instructions
+func BuildTaskPrompt(taskID string, tasks map[string]*Task, workspaceDir string, policy ContextConfig) (string, error) {
+	task := tasks[taskID]
+	if task == nil {
+		return "", fmt.Errorf("task not found: %s", taskID)
+	}
+
+	// Build context: parent chain + sibling results
+	contextInfo := BuildTaskContextWithPolicy(taskID, tasks, workspaceDir, policy)
+
+	// Build full prompt with task context and instructions
+	taskContext := fmt.Sprintf(`
+CURRENT TASK: %s
+CURRENT TASK ID: %s
+
+IMPORTANT: Before starting work, assess if this task should be broken into subtasks.
+If this task involves multiple steps or can be parallelized, you MUST create subtasks first.
+
+`, task.Title, task.ID)
+
+	prompt := task.Description
+	if prompt == "" {
+		prompt = task.Title // Fallback to title if no description
+	}
+
+	// Feed back why the previous attempt was rejected
+	feedback := ""
+	if task.Feedback != "" {
+		feedback = fmt.Sprintf(`
+
+%s
+
+Fix the problems above before finishing the task.
+`, task.Feedback)
+	}
+
+	// Tell the agent which files it may touch
+	scope := ""
+	if patterns := TaskScope(taskID, tasks); len(patterns) > 0 {
+		scope = fmt.Sprintf("\n\nSCOPE: Only create, modify or delete files matching: %s\n", strings.Join(patterns, ", "))
+	}
+
+	return contextInfo + taskContext + prompt + scope + feedback + "\n" + prompts.TaskSystemInstructions, nil
+}
+
+// BuildSummaryPrompt renders the prompt asking a parent task to synthesize its children's results
+func BuildSummaryPrompt(parentID string, tasks map[string]*Task) (string, error) {
+	parent := tasks[parentID]
+	if parent == nil {
+		return "", fmt.Errorf("task not found: %s", parentID)
+	}
+
+	// Completed children in creation order
+	var children []*Task
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID == parentID && t.Status == "completed" {
+			children = append(children, t)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].CreatedBefore(children[j])
+	})
+
+	// Build enriched prompt with child result references
+	var childrenContext strings.Builder
+	childrenContext.WriteString("\n\nYour subtasks have completed. Here are the results:\n\n")
+	for _, t := range children {
+		resultPath := fmt.Sprintf(".hearth/results/%s.md", t.ID)
+		childrenContext.WriteString(fmt.Sprintf("- %s \"%s\" → Result: %s\n", t.ID, t.Title, resultPath))
+	}
+	childrenContext.WriteString("\nPlease read these result files and synthesize them into a final answer for the original task.\n")
+
+	prompt := parent.Description
+	if prompt == "" {
+		prompt = parent.Title
+	}
+
+	taskContext := fmt.Sprintf(`
+ORIGINAL TASK: %s
+TASK ID: %s
+
+`, parent.Title, parent.ID)
+
+	return taskContext + prompt + childrenContext.String() + "\n" + prompts.TaskSystemInstructions, nil
+}
+
+// BuildNextPrompt renders the prompt that would be sent next for a task:
+// the summary prompt for parents, the execution prompt otherwise
+func BuildNextPrompt(taskID string, tasks map[string]*Task, workspaceDir string, policy ContextConfig) (string, error) {
+	for _, t := range tasks {
+		if t.ParentID != nil && *t.ParentID == taskID {
+			return BuildSummaryPrompt(taskID, tasks)
+		}
+	}
+	return BuildTaskPrompt(taskID, tasks, workspaceDir, policy)
+}
+
+// PromptHash returns the sha256 hex digest of a prompt
+func PromptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// PromptsDir returns the directory holding archived prompts for a task
+func PromptsDir(workspaceDir, taskID string) string {
+	return filepath.Join(workspaceDir, ".hearth", "prompts", taskID)
+}
+
+// PromptPath returns the archived prompt for one execution attempt of a task
+func PromptPath(workspaceDir, taskID string, attempt int) string {
+	return filepath.Join(PromptsDir(workspaceDir, taskID), fmt.Sprintf("%d.txt", attempt))
+}
+
+// ArchivePrompt stores the exact prompt sent for an execution attempt
+func ArchivePrompt(workspaceDir, taskID string, attempt int, prompt string) (string, error) {
+	if err := os.MkdirAll(PromptsDir(workspaceDir, taskID), 0755); err != nil {
+		return "", fmt.Errorf("failed to create prompts directory: %w", err)
+	}
+
+	path := PromptPath(workspaceDir, taskID, attempt)
+	if err := os.WriteFile(path, []byte(prompt), 0644); err != nil {
+		return "", fmt.Errorf("failed to archive prompt: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,65 @@
+package hearth
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPromptArchive tests that execution and summary prompts are archived with their hash
+func TestPromptArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	h, err := New(WithWorkspace(tmpDir), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "P", Title: "Parent", Description: "Do it all", Time: now}))
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "C", Title: "Child", ParentID: strPtr("P"), Time: now.Add(time.Second)}))
+
+	// Before running, the next prompt for the parent is its summary prompt
+	next, err := BuildNextPrompt("P", h.GetTasks(), tmpDir, h.Config().Context)
+	assert.NoError(t, err)
+	assert.Contains(t, next, "ORIGINAL TASK: Parent")
+
+	assert.NoError(t, h.Process(&ExecuteTasksRequested{Time: now}))
+
+	for _, e := range h.Engine().GetEvents() {
+		switch ev := e.(type) {
+		case *TaskExecuted:
+			archived, err := os.ReadFile(PromptPath(tmpDir, ev.TaskID, ev.Attempt))
+			assert.NoError(t, err)
+			assert.Contains(t, string(archived), "CURRENT TASK ID: C")
+			assert.Equal(t, PromptHash(string(archived)), ev.PromptHash)
+		case *SummaryGenerated:
+			archived, err := os.ReadFile(PromptPath(tmpDir, ev.ParentTaskID, ev.Attempt))
+			assert.NoError(t, err)
+			assert.Contains(t, string(archived), "- C \"Child\" → Result: .hearth/results/C.md")
+			assert.Equal(t, PromptHash(string(archived)), ev.PromptHash)
+		}
+	}
+}
+
+// TestBuildTaskPrompt_MatchesExecution tests that the rendered prompt is exactly what gets sent
+func TestBuildTaskPrompt_MatchesExecution(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasks := map[string]*Task{
+		"T-1": {ID: "T-1", Title: "Solo", Description: "Just this", Status: "todo"},
+	}
+
+	rendered, err := BuildTaskPrompt("T-1", tasks, tmpDir, DefaultConfig().Context)
+	assert.NoError(t, err)
+
+	logging := &LoggingCaller{Caller: &MockClaudeCaller{}, WorkspaceDir: tmpDir, TaskID: "T-1"}
+	_, err = ExecuteTask("T-1", tasks, tmpDir, logging)
+	assert.NoError(t, err)
+
+	sent, err := os.ReadFile(PromptPath(tmpDir, "T-1", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, rendered, string(sent))
+
+	_, err = BuildTaskPrompt("missing", tasks, tmpDir, DefaultConfig().Context)
+	assert.Error(t, err)
+}
@@ -1,9 +1,20 @@
 package hearth
 
 import (
+	"fmt"
+
 	"github.com/cumulusrpg/atmos"
 )
 
+// TaskCreationValidator rejects tasks whose ID is already taken
+type TaskCreationValidator struct{}
+
+func (v *TaskCreationValidator) ValidateTyped(engine *atmos.Engine, event *TaskCreated) bool {
+	state := engine.GetState("hearth").(HearthState)
+	_, exists := state.Tasks[event.TaskID]
+	return !exists
+}
+
 // TaskCompletionValidator ensures a task can only be completed if it has no incomplete children
 type TaskCompletionValidator struct{}
 
@@ -27,6 +38,37 @@ func (v *TaskCompletionValidator) ValidateTyped(engine *atmos.Engine, event *Tas
 	return !hasIncompleteChildren
 }
 
+// hearthReducers builds HearthState: New registers them on the engine, foldEvent applies them directly
+var hearthReducers = map[string]atmos.StateReducer{
+	"task_created":        reduceTaskCreated,
+	"task_started":        reduceTaskStarted,
+	"task_completed":      reduceTaskCompleted,
+	"next_task_selected":  reduceNextTaskSelected,
+	"task_executed":       reduceTaskExecuted,
+	"verification_failed": reduceVerificationFailed,
+	"task_reviewed":       reduceTaskReviewed,
+	"task_escalated":      reduceTaskEscalated,
+	"task_failed":         reduceTaskFailed,
+	"task_reverted":       reduceTaskReverted,
+	"merge_conflict":      reduceMergeConflict,
+	"task_deleted":        reduceTaskDeleted,
+	"task_reopened":       reduceTaskReopened,
+}
+
+// newHearthState returns the state before any event
+func newHearthState() HearthState {
+	return HearthState{Tasks: make(map[string]*Task), Deleted: make(map[string]*Task)}
+}
+
+// foldEvent applies one event to a state, as the engine's reducers would
+// The reducers don't use the engine, so views can fold events without one
+func foldEvent(state HearthState, event atmos.Event) HearthState {
+	if reducer, ok := hearthReducers[event.Type()]; ok {
+		return reducer(nil, state, event).(HearthState)
+	}
+	return state
+}
+
 // reduceTaskCreated handles TaskCreated events
 func reduceTaskCreated(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
 	s := state.(HearthState)
@@ -37,7 +79,13 @@ func reduceTaskCreated(engine *atmos.Engine, state interface{}, event atmos.Even
 		Title:       e.Title,
 		Description: e.Description,
 		ParentID:    e.ParentID,
+		Verify:      e.Verify,
+		Review:      e.Review,
+		Agent:       e.Agent,
+		Model:       e.Model,
+		Scope:       e.Scope,
 		Status:      "todo",
+		Seq:         e.Seq,
 		CreatedAt:   e.Time,
 	}
 
@@ -93,6 +141,140 @@ func reduceNextTaskSelected(engine *atmos.Engine, state interface{}, event atmos
 
 func reduceTaskExecuted(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
 	s := state.(HearthState)
+	e := event.(*TaskExecuted)
+
 	// TaskExecuted event is recorded in log (result path available for context building)
+	// Checkpoint commits are tracked so the task can be reverted, changed files for context
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		if task.BaseCommit == "" {
+			task.BaseCommit = e.BaseCommit
+		}
+		if e.Commit != "" {
+			task.Commits = append(task.Commits, e.Commit)
+		}
+		if len(e.Files) > 0 {
+			task.Files = mergeFileChanges(task.Files, e.Files)
+		}
+	}
+
+	return s
+}
+
+// reduceTaskReverted forgets the task's reverted checkpoint commits
+func reduceTaskReverted(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskReverted)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		var remaining []string
+		for _, commit := range task.Commits {
+			if !contains(e.Commits, commit) {
+				remaining = append(remaining, commit)
+			}
+		}
+		task.Commits = remaining
+	}
+
+	return s
+}
+
+// reduceTaskEscalated records the escalation rung the task now runs on
+func reduceTaskEscalated(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskEscalated)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		task.Rung = e.Rung
+		task.Failures = 0
+		task.Status = "in-progress"
+	}
+
+	return s
+}
+
+// reduceTaskFailed marks a task as failed
+func reduceTaskFailed(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskFailed)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		task.Status = "failed"
+	}
+
+	return s
+}
+
+// reduceVerificationFailed puts the task back in the queue with the failure as feedback
+func reduceVerificationFailed(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*VerificationFailed)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		task.Status = "todo"
+		task.Failures++
+		task.Feedback = fmt.Sprintf("PREVIOUS ATTEMPT FAILED VERIFICATION:\n$ %s\nexit code %d\n%s", e.Command, e.ExitCode, e.Output)
+	}
+
+	return s
+}
+
+// reduceTaskReviewed puts the task back in the queue when the reviewer requested changes
+func reduceTaskReviewed(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskReviewed)
+
+	if task, exists := s.Tasks[e.TaskID]; exists && e.Verdict == VerdictRequestChanges {
+		task.Status = "todo"
+		task.Reviews++
+		task.Feedback = "REVIEWER REQUESTED CHANGES:\n" + e.Comments
+	}
+
+	return s
+}
+
+// reduceMergeConflict marks a task whose worktree could not be merged as failed
+func reduceMergeConflict(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*MergeConflict)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		task.Status = "failed"
+	}
+
+	return s
+}
+
+// ============================================================================
+// UNDO REDUCERS - Compensating events
+// ============================================================================
+
+// reduceTaskDeleted removes a task whose creation was undone
+func reduceTaskDeleted(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskDeleted)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		if s.Deleted == nil {
+			s.Deleted = make(map[string]*Task)
+		}
+		s.Deleted[e.TaskID] = task
+		delete(s.Tasks, e.TaskID)
+	}
+
+	return s
+}
+
+// reduceTaskReopened restores a task's earlier status
+func reduceTaskReopened(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	s := state.(HearthState)
+	e := event.(*TaskReopened)
+
+	if task, exists := s.Tasks[e.TaskID]; exists {
+		task.Status = e.Status
+		if e.Status != "completed" {
+			task.CompletedAt = nil
+		}
+	}
+
 	return s
 }
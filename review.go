@@ -0,0 +1,107 @@
+package hearth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Review verdicts
+const (
+	VerdictApprove        = "approve"
+	VerdictRequestChanges = "request_changes"
+	VerdictNone           = "none" // the review could not run or gave no verdict: not approved
+)
+
+// maxDiffLength bounds the git diff included in review prompts
+const maxDiffLength = 20000
+
+var verdictLine = regexp.MustCompile(`(?mi)^\s*VERDICT:\s*(APPROVE|REQUEST[_ ]CHANGES)\s*$`)
+
+// ReviewEnabled reports whether a task's result is reviewed: its own setting, inherited
+// from the nearest ancestor that set one, else review.enabled
+func ReviewEnabled(taskID string, tasks map[string]*Task, cfg *Config) bool {
+	for task := tasks[taskID]; task != nil; task = parentOf(task, tasks) {
+		if task.Review != nil {
+			return *task.Review
+		}
+	}
+	return cfg.Review.Enabled
+}
+
+// ReviewPath returns the file holding all review rounds of a task
+func ReviewPath(workspaceDir, taskID string) string {
+	return filepath.Join(workspaceDir, ".hearth", "reviews", taskID+".md")
+}
+
+// BuildReviewPrompt renders the prompt asking a reviewer to judge a task's result
+func BuildReviewPrompt(taskID string, tasks map[string]*Task, result, diff string) (string, error) {
+	task := tasks[taskID]
+	if task == nil {
+		return "", fmt.Errorf("task not found: %s", taskID)
+	}
+
+	description := task.Description
+	if description == "" {
+		description = task.Title
+	}
+	if diff == "" {
+		diff = "(no changes)"
+	}
+
+	return fmt.Sprintf(`You are reviewing work done by another agent. Do not modify any files.
+
+REVIEWED TASK: %s
+TASK ID: %s
+
+TASK DESCRIPTION:
+%s
+
+AGENT RESULT:
+%s
+
+CHANGES (git diff):
+%s
+
+Decide whether the work fully and correctly completes the task.
+End your answer with exactly one line:
+VERDICT: APPROVE
+or
+VERDICT: REQUEST_CHANGES
+When requesting changes, list what must be fixed above the verdict line.
+`, task.Title, task.ID, description, result, diff), nil
+}
+
+// ParseVerdict extracts the verdict from a reviewer response
+// A response without a verdict line (e.g. cut off) gives VerdictNone, which does not approve
+func ParseVerdict(response string) string {
+	matches := verdictLine.FindAllStringSubmatch(response, -1)
+	if len(matches) == 0 {
+		return VerdictNone
+	}
+	if strings.EqualFold(matches[len(matches)-1][1], "APPROVE") {
+		return VerdictApprove
+	}
+	return VerdictRequestChanges
+}
+
+// appendReview adds one review round to the task's review file
+func appendReview(workspaceDir, taskID string, round int, verdict, response string) (string, error) {
+	path := ReviewPath(workspaceDir, taskID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create reviews directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open review file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintf(file, "## Round %d: %s\n\n%s\n\n", round, verdict, strings.TrimSpace(response)); err != nil {
+		return "", fmt.Errorf("failed to write review: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,89 @@
+package hearth_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReview_ChangesAreFedBack tests that requested changes re-run the task with the
+// review as feedback, and verdicts are stored as events and in the review file
+func TestReview_ChangesAreFedBack(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Feature", Review: true, Match: func(c hearthtest.Call) bool { return c.Attempt == 1 },
+			Response: "Missing tests for the error path.\nVERDICT: REQUEST_CHANGES"},
+		{Title: "Feature", Review: true, Response: "Looks good.\nVERDICT: APPROVE"},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("review.enabled", "true"))
+	no := false
+	assert.NoError(t, ws.Hearth.Process(&hearth.TaskCreated{TaskID: "T-1", Title: "Feature", Time: ws.Hearth.Now()}))
+	assert.NoError(t, ws.Hearth.Process(&hearth.TaskCreated{TaskID: "T-2", Title: "Chore", Review: &no, Time: ws.Hearth.Now()}))
+
+	ws.Run()
+
+	ws.AssertTree(`
+		✓ Feature
+		✓ Chore
+	`)
+	assert.Equal(t, []string{"Feature", "Feature (review)", "Feature", "Feature (review)", "Chore"}, ws.Agent.CalledTitles())
+	assert.Contains(t, ws.Agent.Calls()[2].Prompt, "REVIEWER REQUESTED CHANGES")
+	assert.Contains(t, ws.Agent.Calls()[2].Prompt, "Missing tests for the error path.")
+
+	var verdicts []string
+	for _, event := range ws.Hearth.Engine().GetEvents() {
+		if reviewed, ok := event.(*hearth.TaskReviewed); ok {
+			verdicts = append(verdicts, reviewed.Verdict)
+		}
+	}
+	assert.Equal(t, []string{hearth.VerdictRequestChanges, hearth.VerdictApprove}, verdicts)
+
+	review, err := os.ReadFile(hearth.ReviewPath(ws.Dir, "T-1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(review), "## Round 1: request_changes")
+	assert.Contains(t, string(review), "## Round 2: approve")
+}
+
+// TestParseVerdict tests verdict extraction from reviewer responses
+func TestParseVerdict(t *testing.T) {
+	assert.Equal(t, hearth.VerdictApprove, hearth.ParseVerdict("fine\nVERDICT: APPROVE"))
+	assert.Equal(t, hearth.VerdictRequestChanges, hearth.ParseVerdict("fix it\nverdict: request changes\n"))
+	assert.Equal(t, hearth.VerdictNone, hearth.ParseVerdict("cut off before the verdi"))
+}
+
+// TestReview_FailsWithoutVerdict tests that a reviewer that cannot be called, or answers
+// without a verdict, fails the task instead of approving it
+func TestReview_FailsWithoutVerdict(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Unreachable", Review: true, FailOnAttempts: []int{1}},
+		{Title: "Cut off", Review: true, Response: "The change looks"},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("review.enabled", "true"))
+	ws.AddTask("Unreachable", "", "")
+	ws.AddTask("Cut off", "", "")
+	ws.AddTask("Reviewed", "", "")
+
+	ws.Run()
+
+	ws.AssertTree(`
+		✗ Unreachable
+		✗ Cut off
+		✓ Reviewed
+	`)
+	var verdicts, errors []string
+	for _, event := range ws.Hearth.Engine().GetEvents() {
+		switch e := event.(type) {
+		case *hearth.TaskReviewed:
+			verdicts = append(verdicts, e.Verdict)
+		case *hearth.TaskFailed:
+			errors = append(errors, e.Error)
+		}
+	}
+	assert.Equal(t, []string{hearth.VerdictNone, hearth.VerdictNone, hearth.VerdictApprove}, verdicts)
+	if assert.Len(t, errors, 2) {
+		assert.Contains(t, errors[0], "review failed: fake agent: scripted failure")
+		assert.Equal(t, "review failed: the reviewer's response has no VERDICT line", errors[1])
+	}
+}
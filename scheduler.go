@@ -0,0 +1,26 @@
+package hearth
+
+import "fmt"
+
+// Scheduler picks the next task to execute
+// Next returns nil when no task is eligible
+type Scheduler interface {
+	Next(tasks []*Task) *Task
+}
+
+// NewScheduler returns the scheduler for a configured name
+func NewScheduler(name string) (Scheduler, error) {
+	switch name {
+	case "", SchedulerDepthFirst:
+		return &DepthFirstScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scheduler: %s (use %s)", name, SchedulerDepthFirst)
+	}
+}
+
+// DepthFirstScheduler completes whole subtrees before moving on to the next sibling
+type DepthFirstScheduler struct{}
+
+func (s *DepthFirstScheduler) Next(tasks []*Task) *Task {
+	return findNextTask(tasks)
+}
@@ -0,0 +1,188 @@
+package hearth
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Scope enforcement modes for scope.enforce
+const (
+	ScopeWarn = "warn" // log changes outside the declared scope
+	ScopeFail = "fail" // fail the execution (escalating like any other failure)
+)
+
+// TaskScope returns the path globs a task may touch: its own, or inherited from
+// the nearest ancestor that declared some (nil = unscoped)
+func TaskScope(taskID string, tasks map[string]*Task) []string {
+	for task := tasks[taskID]; task != nil; task = parentOf(task, tasks) {
+		if len(task.Scope) > 0 {
+			return task.Scope
+		}
+	}
+	return nil
+}
+
+// ValidateScope checks that scope globs are well-formed and relative to the workspace
+func ValidateScope(scope []string) error {
+	for _, pattern := range scope {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("invalid scope: empty pattern")
+		}
+		if path.IsAbs(pattern) || strings.HasPrefix(path.Clean(pattern), "..") {
+			return fmt.Errorf("invalid scope %q: must be relative to the workspace", pattern)
+		}
+		for _, segment := range splitPath(pattern) {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid scope %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MatchScope reports whether a slash-separated path matches a scope glob
+// Globs use path.Match syntax per segment, plus "**" for any number of segments;
+// a glob without wildcards also matches everything below it (a directory)
+func MatchScope(pattern, name string) bool {
+	segments := splitPath(pattern)
+	if len(literalPrefix(segments)) == len(segments) {
+		segments = append(segments, "**")
+	}
+	return matchSegments(segments, splitPath(name))
+}
+
+// InScope reports whether a path matches any of the globs
+func InScope(scope []string, name string) bool {
+	for _, pattern := range scope {
+		if MatchScope(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// OutOfScope returns the changed files that match none of the globs
+func OutOfScope(scope []string, files []FileChange) []string {
+	var outside []string
+	for _, file := range files {
+		if !InScope(scope, file.Path) {
+			outside = append(outside, file.Path)
+		}
+	}
+	return outside
+}
+
+// ScopesOverlap reports whether two scopes might match a common path
+// The check is conservative: globs overlap unless their literal leading
+// segments differ (internal/auth/** and internal/db/** do not overlap)
+func ScopesOverlap(a, b []string) bool {
+	for _, pa := range a {
+		for _, pb := range b {
+			if globsOverlap(pa, pb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// globsOverlap compares the literal leading segments of two globs
+func globsOverlap(a, b string) bool {
+	sa, sb := literalPrefix(splitPath(a)), literalPrefix(splitPath(b))
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// literalPrefix returns the leading segments of a glob that contain no wildcards
+func literalPrefix(segments []string) []string {
+	for i, segment := range segments {
+		if isGlob(segment) {
+			return segments[:i]
+		}
+	}
+	return segments
+}
+
+// isGlob reports whether a segment contains wildcard characters
+func isGlob(segment string) bool {
+	return strings.ContainsAny(segment, "*?[\\")
+}
+
+// matchSegments matches path segments against glob segments, expanding "**"
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" matches zero or more segments
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// splitPath splits a slash-separated path into non-empty segments
+func splitPath(p string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path.Clean("/"+strings.TrimPrefix(p, "./")), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// scopeBlocked reports whether a task's scope overlaps a task that is running right now
+// Only leaves that are in progress count; parents stay in progress while their children run
+// Tasks without a scope are never blocked and never block others
+func scopeBlocked(task *Task, taskMap map[string]*Task) bool {
+	return len(scopeBlockers(task, taskMap)) > 0
+}
+
+// scopeBlockers returns the running tasks whose scope overlaps a task's, in creation order
+func scopeBlockers(task *Task, taskMap map[string]*Task) []*Task {
+	scope := TaskScope(task.ID, taskMap)
+	if len(scope) == 0 {
+		return nil
+	}
+
+	var blockers []*Task
+	for _, other := range taskMap {
+		if other.ID == task.ID || other.Status != "in-progress" || hasChildren(other.ID, taskMap) {
+			continue
+		}
+		if otherScope := TaskScope(other.ID, taskMap); len(otherScope) > 0 && ScopesOverlap(scope, otherScope) {
+			blockers = append(blockers, other)
+		}
+	}
+	sort.Slice(blockers, func(i, j int) bool {
+		return blockers[i].CreatedBefore(blockers[j])
+	})
+	return blockers
+}
+
+// hasChildren reports whether any task has id as its parent
+func hasChildren(id string, taskMap map[string]*Task) bool {
+	for _, task := range taskMap {
+		if task.ParentID != nil && *task.ParentID == id {
+			return true
+		}
+	}
+	return false
+}
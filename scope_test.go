@@ -0,0 +1,111 @@
+package hearth_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMatchScope tests glob matching with ** and directory prefixes
+func TestMatchScope(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		match         bool
+	}{
+		{"internal/auth/**", "internal/auth/login.go", true},
+		{"internal/auth/**", "internal/auth/oauth/google.go", true},
+		{"internal/auth/**", "internal/db/conn.go", false},
+		{"internal/auth", "internal/auth/login.go", true},
+		{"**/*_test.go", "pkg/a/b_test.go", true},
+		{"**/*_test.go", "pkg/a/b.go", false},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", false},
+		{"./docs/**", "docs/guide.md", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, hearth.MatchScope(c.pattern, c.path), "%s ~ %s", c.pattern, c.path)
+	}
+
+	assert.NoError(t, hearth.ValidateScope([]string{"internal/**", "*.go"}))
+	assert.Error(t, hearth.ValidateScope([]string{"/etc/**"}))
+	assert.Error(t, hearth.ValidateScope([]string{"../other/**"}))
+	assert.Error(t, hearth.ValidateScope([]string{"src/[a"}))
+}
+
+// TestScopesOverlap tests the conservative overlap check used by the scheduler
+func TestScopesOverlap(t *testing.T) {
+	assert.False(t, hearth.ScopesOverlap([]string{"internal/auth/**"}, []string{"internal/db/**"}))
+	assert.True(t, hearth.ScopesOverlap([]string{"internal/auth/**"}, []string{"internal/auth/login.go"}))
+	assert.True(t, hearth.ScopesOverlap([]string{"internal/**"}, []string{"internal/db/**"}))
+	assert.True(t, hearth.ScopesOverlap([]string{"**/*.go"}, []string{"docs/**"}))
+	assert.True(t, hearth.ScopesOverlap([]string{"docs/**", "cmd/**"}, []string{"cmd/hearth/main.go"}))
+}
+
+// TestScheduler_SkipsOverlappingScopes tests that a task waits while a running task
+// may touch the same files, and unscoped tasks are not held back
+func TestScheduler_SkipsOverlappingScopes(t *testing.T) {
+	running := &hearth.Task{ID: "R", Status: "in-progress", Scope: []string{"internal/auth/**"}, Seq: 1}
+	auth := &hearth.Task{ID: "A", Status: "todo", Scope: []string{"internal/auth/login.go"}, Seq: 2}
+	db := &hearth.Task{ID: "D", Status: "todo", Scope: []string{"internal/db/**"}, Seq: 3}
+	free := &hearth.Task{ID: "F", Status: "todo", Seq: 4}
+
+	scheduler := &hearth.DepthFirstScheduler{}
+	assert.Equal(t, "D", scheduler.Next([]*hearth.Task{running, auth, db, free}).ID)
+	assert.Equal(t, "F", scheduler.Next([]*hearth.Task{running, auth, free}).ID)
+
+	running.Status = "completed"
+	assert.Equal(t, "A", scheduler.Next([]*hearth.Task{running, auth, db, free}).ID)
+}
+
+// TestScope_OutOfScopeChanges tests that changes outside the scope are recorded,
+// and fail the task when scope.enforce is fail
+func TestScope_OutOfScopeChanges(t *testing.T) {
+	for _, enforce := range []string{hearth.ScopeWarn, hearth.ScopeFail} {
+		t.Run(enforce, func(t *testing.T) {
+			ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+				{Title: "Docs", Files: map[string]string{"docs/a.md": "a", "src/main.go": "package main"}},
+			})
+			assert.NoError(t, ws.Hearth.Config().Set("scope.enforce", enforce))
+			assert.NoError(t, ws.Hearth.Process(&hearth.TaskCreated{TaskID: "T-1", Title: "Docs", Scope: []string{"docs/**"}, Time: ws.Hearth.Now()}))
+
+			ws.Run()
+
+			var outside []string
+			for _, event := range ws.Hearth.Engine().GetEvents() {
+				if executed, ok := event.(*hearth.TaskExecuted); ok {
+					outside = executed.OutOfScope
+				}
+			}
+			assert.Equal(t, []string{"src/main.go"}, outside)
+			assert.Contains(t, ws.Agent.Calls()[0].Prompt, "SCOPE: Only create, modify or delete files matching: docs/**")
+
+			if enforce == hearth.ScopeFail {
+				ws.AssertTree(`✗ Docs`)
+			} else {
+				ws.AssertTree(`✓ Docs`)
+			}
+		})
+	}
+}
+
+// TestScheduler_LogsScopeWaits tests that a task held back by a stale in-progress task is
+// logged with the task holding its scope, instead of waiting silently
+func TestScheduler_LogsScopeWaits(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := hearth.NewLogger(&buf, hearth.LogFormatHuman, "info")
+	assert.NoError(t, err)
+	h, err := hearth.New(hearth.WithCaller(&hearth.MockClaudeCaller{}), hearth.WithLogger(logger))
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: "R", Title: "Interrupted", Scope: []string{"internal/auth/**"}, Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.TaskStarted{TaskID: "R", Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.TaskCreated{TaskID: "A", Title: "Auth", Scope: []string{"internal/auth/login.go"}, Time: h.Now()}))
+	assert.NoError(t, h.Process(&hearth.ExecuteTasksRequested{Time: h.Now()}))
+
+	assert.Equal(t, "todo", h.GetTask("A").Status)
+	assert.Contains(t, buf.String(), "⚠ "+hearth.MsgScopeBlocked+" task_id=A held_by=R")
+	assert.Contains(t, buf.String(), "hearth doctor")
+}
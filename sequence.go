@@ -0,0 +1,89 @@
+package hearth
+
+import "github.com/cumulusrpg/atmos"
+
+// Sequenced is implemented by events that carry a repository-assigned sequence number
+type Sequenced interface {
+	Sequence() int64
+	SetSequence(seq int64)
+}
+
+// RunTagged is implemented by events that record the run they were emitted in
+type RunTagged interface {
+	Run() string
+	SetRun(runID string)
+}
+
+// EventMeta carries metadata assigned when an event is stored
+// Every Hearth event embeds it
+type EventMeta struct {
+	Seq      int64  `json:",omitempty"` // position in the event log, starting at 1
+	RunID    string `json:",omitempty"` // `hearth run` invocation that emitted the event ("" = outside a run)
+	PrevHash string `json:",omitempty"` // hash of the previous event ("" for the first hashed event)
+	Hash     string `json:",omitempty"` // hash of this event's contents, see EventHash
+}
+
+// Sequence returns the event's position in the event log
+func (m *EventMeta) Sequence() int64 { return m.Seq }
+
+// SetSequence sets the event's position in the event log
+func (m *EventMeta) SetSequence(seq int64) { m.Seq = seq }
+
+// Run returns the run the event was emitted in
+func (m *EventMeta) Run() string { return m.RunID }
+
+// SetRun sets the run the event was emitted in
+func (m *EventMeta) SetRun(runID string) { m.RunID = runID }
+
+// SequencedRepository wraps a repository, numbering events as they are added
+// FileRepository numbers events itself (under its file lock), so it needs no wrapper
+type SequencedRepository struct {
+	atmos.EventRepository
+}
+
+// Add assigns the next sequence number and the current run, then stores the event
+func (r *SequencedRepository) Add(engine *atmos.Engine, event atmos.Event) error {
+	stampEvent(engine, event, r.EventRepository.GetAll(engine))
+	return r.EventRepository.Add(engine, event)
+}
+
+// GetAll returns all events, numbering any that predate sequencing by position
+func (r *SequencedRepository) GetAll(engine *atmos.Engine) []atmos.Event {
+	events := r.EventRepository.GetAll(engine)
+	numberEvents(events)
+	return events
+}
+
+// stampEvent numbers an event following existing, tags it with the engine's run and
+// links it into the hash chain (last, since the hash covers the other fields)
+// An event that already names a run keeps it
+func stampEvent(engine *atmos.Engine, event atmos.Event, existing []atmos.Event) {
+	if s, ok := event.(Sequenced); ok {
+		s.SetSequence(nextSequence(existing))
+	}
+	if r, ok := event.(RunTagged); ok && r.Run() == "" {
+		r.SetRun(getRunID(engine))
+	}
+	chainEvent(event, existing)
+}
+
+// nextSequence returns the sequence number following events
+func nextSequence(events []atmos.Event) int64 {
+	next := int64(len(events)) + 1
+	if len(events) > 0 {
+		if s, ok := events[len(events)-1].(Sequenced); ok && s.Sequence() >= next {
+			next = s.Sequence() + 1
+		}
+	}
+	return next
+}
+
+// numberEvents numbers events stored without a sequence (logs written before sequencing)
+// by their position in the log
+func numberEvents(events []atmos.Event) {
+	for i, event := range events {
+		if s, ok := event.(Sequenced); ok && s.Sequence() == 0 {
+			s.SetSequence(int64(i) + 1)
+		}
+	}
+}
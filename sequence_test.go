@@ -0,0 +1,63 @@
+package hearth
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSequence_OrdersTasksWithSharedTimestamps tests that tasks created at the same instant
+// keep their creation order because ordering uses the event sequence, not the clock
+func TestSequence_OrdersTasksWithSharedTimestamps(t *testing.T) {
+	clock := fixedClock{now: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	for name, opts := range map[string][]Option{
+		"file":      {WithWorkspace(t.TempDir())},
+		"in-memory": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := New(append(opts, WithClock(clock), WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))...)
+			assert.NoError(t, err)
+
+			// IDs sort the opposite way from creation order
+			for _, id := range []string{"Z", "M", "A"} {
+				assert.NoError(t, h.Process(&TaskCreated{TaskID: id, Title: id, Time: h.Now()}))
+			}
+
+			assert.Equal(t, int64(1), h.GetTask("Z").Seq)
+			assert.Equal(t, int64(3), h.GetTask("A").Seq)
+			assert.Equal(t, "Z", h.GetNextTask().ID)
+
+			steps, err := h.Plan()
+			assert.NoError(t, err)
+			var order []string
+			for _, step := range steps {
+				order = append(order, step.Task.ID)
+			}
+			assert.Equal(t, []string{"Z", "M", "A"}, order)
+		})
+	}
+}
+
+// TestSequence_NumbersLegacyLogs tests that events stored before sequencing are numbered
+// by position and new events continue from there
+func TestSequence_NumbersLegacyLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".hearth"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".hearth", "events.json"), []byte(`[
+  {"type": "task_created", "data": {"TaskID": "B", "Title": "B", "Time": "2025-01-01T00:00:00Z"}},
+  {"type": "task_created", "data": {"TaskID": "A", "Title": "A", "Time": "2025-01-01T00:00:00Z"}}
+]`), 0644))
+
+	h, err := NewHearth(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), h.GetTask("B").Seq)
+	assert.Equal(t, int64(2), h.GetTask("A").Seq)
+
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "C", Title: "C", Time: h.Now()}))
+	assert.Equal(t, int64(3), h.GetTask("C").Seq)
+}
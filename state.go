@@ -4,7 +4,8 @@ import "time"
 
 // HearthState holds all tasks
 type HearthState struct {
-	Tasks map[string]*Task
+	Tasks   map[string]*Task
+	Deleted map[string]*Task // deleted tasks, kept so their IDs are never handed out again
 }
 
 // Task represents a task in the system
@@ -13,7 +14,29 @@ type Task struct {
 	Title       string
 	Description string
 	ParentID    *string
-	Status      string
+	Verify      string       // verification command chosen for this task ("" = inherited, see VerifyCommand)
+	Review      *bool        // review setting for this task (nil = inherited, see ReviewEnabled)
+	Agent       string       // agent chosen for this task ("" = inherited, see SelectAgent)
+	Model       string       // model chosen for this task ("" = inherited, see SelectAgent)
+	Scope       []string     // path globs declared for this task (nil = inherited, see TaskScope)
+	Status      string       // todo, in-progress, completed or failed
+	Rung        int          // escalation rung the task runs on (0 = not escalated)
+	Feedback    string       // why the last attempt was rejected, fed into the next prompt
+	Failures    int          // verification failures on the current rung
+	Reviews     int          // review rounds that requested changes
+	BaseCommit  string       // HEAD before the task first ran (git.checkpoint only)
+	Commits     []string     // checkpoint commits not yet reverted, oldest first
+	Files       []FileChange // files changed across all executions of the task
+	Seq         int64        // sequence number of the TaskCreated event
 	CreatedAt   time.Time
 	CompletedAt *time.Time
 }
+
+// CreatedBefore reports whether t was created before other
+// Tasks are ordered by event sequence; CreatedAt is only used when either lacks one
+func (t *Task) CreatedBefore(other *Task) bool {
+	if t.Seq != 0 && other.Seq != 0 {
+		return t.Seq < other.Seq
+	}
+	return t.CreatedAt.Before(other.CreatedAt)
+}
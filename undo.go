@@ -0,0 +1,162 @@
+package hearth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// UndoStep is one event being undone and the compensating event that undoes it
+type UndoStep struct {
+	Event        EventRecord
+	Compensation atmos.Event // nil when the event left nothing to undo (e.g. a run starting)
+}
+
+// UndoPlan lists the events an undo covers, newest first
+type UndoPlan struct {
+	Steps []UndoStep
+}
+
+// Compensations returns the events that apply the plan, in the order to emit them,
+// closed by an EventsUndone recording every event the plan covers
+func (p *UndoPlan) Compensations(now time.Time) []atmos.Event {
+	var events []atmos.Event
+	undone := &EventsUndone{Time: now}
+	for _, step := range p.Steps {
+		if step.Compensation != nil {
+			events = append(events, step.Compensation)
+		}
+		undone.Seqs = append(undone.Seqs, step.Event.Seq)
+	}
+	return append(events, undone)
+}
+
+// UndoableEvents returns the events an undo can still reach, oldest first:
+// everything except undo bookkeeping and the events earlier undos covered
+func (h *Hearth) UndoableEvents() []EventRecord {
+	events := h.engine.GetEvents()
+	undone := make(map[int64]bool)
+	for _, event := range events {
+		if e, ok := event.(*EventsUndone); ok {
+			for _, seq := range e.Seqs {
+				undone[seq] = true
+			}
+		}
+	}
+
+	var records []EventRecord
+	for _, event := range events {
+		record := NewEventRecord(event)
+		if !isUndoEvent(event) && !undone[record.Seq] {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// PlanUndoLast plans undoing the last n events that have not been undone yet
+func (h *Hearth) PlanUndoLast(n int) (*UndoPlan, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("nothing to undo: --last must be at least 1")
+	}
+	records := h.UndoableEvents()
+	if len(records) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	if n > len(records) {
+		n = len(records)
+	}
+	return h.planUndo(records[len(records)-n:])
+}
+
+// PlanUndoUntil plans undoing every event from seq onwards that has not been undone yet
+func (h *Hearth) PlanUndoUntil(seq int64) (*UndoPlan, error) {
+	var selected []EventRecord
+	for _, record := range h.UndoableEvents() {
+		if record.Seq >= seq {
+			selected = append(selected, record)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("nothing to undo from event #%d", seq)
+	}
+	return h.planUndo(selected)
+}
+
+// planUndo builds compensating events for the selected events (oldest first)
+// The selection is always the newest undoable events, so a task's later events are undone with it
+// Events with side effects outside the log - agent calls, commands, commits, merges -
+// cannot be undone, so the whole plan is refused when one is selected
+func (h *Hearth) planUndo(selected []EventRecord) (*UndoPlan, error) {
+	deleted := make(map[string]bool)
+	for _, record := range selected {
+		if created, ok := record.Event.(*TaskCreated); ok {
+			deleted[created.TaskID] = true
+		}
+	}
+
+	plan := &UndoPlan{}
+	now := h.Now()
+	for i := len(selected) - 1; i >= 0; i-- {
+		record := selected[i]
+		if reason := undoRefusal(record.Event); reason != "" {
+			return nil, fmt.Errorf("cannot undo event #%d (%s): %s", record.Seq, record.Type, reason)
+		}
+
+		step := UndoStep{Event: record}
+		switch e := record.Event.(type) {
+		case *TaskCreated:
+			// Subtasks were created later, so they are always undone first
+			step.Compensation = &TaskDeleted{TaskID: e.TaskID, Undoes: record.Seq, Time: now}
+		case *TaskStarted, *TaskCompleted, *TaskFailed, *NextTaskSelected:
+			taskID := EventTaskID(e)
+			if taskID == "" || deleted[taskID] {
+				break // no task selected, or the task itself is being deleted
+			}
+			before, err := h.StateAt(AtSeq(record.Seq - 1))
+			if err != nil {
+				return nil, err
+			}
+			if task := before.Tasks[taskID]; task != nil {
+				step.Compensation = &TaskReopened{TaskID: taskID, Status: task.Status, Undoes: record.Seq, Time: now}
+			}
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
+
+// Undo emits the compensating events of a plan, newest event first
+func (h *Hearth) Undo(plan *UndoPlan) error {
+	for _, event := range plan.Compensations(h.Now()) {
+		if err := h.Process(event); err != nil {
+			return fmt.Errorf("failed to record %s: %w", event.Type(), err)
+		}
+	}
+	return nil
+}
+
+// undoRefusal explains why an event cannot be undone ("" when it can)
+func undoRefusal(event atmos.Event) string {
+	switch event.(type) {
+	case *TaskExecuted, *SummaryGenerated, *TaskReviewed:
+		return "an agent already ran and may have changed files"
+	case *TaskVerified:
+		return "the verification command already ran"
+	case *TaskMerged, *MergeConflict, *TaskReverted:
+		return "it changed the git repository"
+	case *VerificationFailed, *TaskEscalated:
+		return "it is part of an execution's retry loop"
+	}
+	return ""
+}
+
+// isUndoEvent reports whether an event was emitted by an undo (undos cannot be undone)
+func isUndoEvent(event atmos.Event) bool {
+	switch event.(type) {
+	case *TaskDeleted, *TaskReopened, *EventsUndone:
+		return true
+	}
+	return false
+}
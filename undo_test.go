@@ -0,0 +1,84 @@
+package hearth
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUndo_CompensatesCreationAndCompletion tests that undo deletes created tasks and restores
+// statuses through new events, never selecting the same event twice
+func TestUndo_CompensatesCreationAndCompletion(t *testing.T) {
+	h, err := New(WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	parent := "A"
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "A", Title: "A", Time: now}))                    // #1
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "B", Title: "B", ParentID: &parent, Time: now})) // #2
+	assert.NoError(t, h.Process(&TaskCreated{TaskID: "C", Title: "C", Time: now}))                    // #3
+	assert.NoError(t, h.Process(&TaskCompleted{TaskID: "C", Time: now}))                              // #4
+
+	// Completing the wrong task
+	plan, err := h.PlanUndoLast(1)
+	assert.NoError(t, err)
+	if assert.Len(t, plan.Steps, 1) {
+		assert.Equal(t, int64(4), plan.Steps[0].Event.Seq)
+		reopened, ok := plan.Steps[0].Compensation.(*TaskReopened)
+		if assert.True(t, ok) {
+			assert.Equal(t, "C", reopened.TaskID)
+			assert.Equal(t, "todo", reopened.Status)
+			assert.Equal(t, int64(4), reopened.Undoes)
+		}
+	}
+	assert.NoError(t, h.Undo(plan))
+	assert.Equal(t, "todo", h.GetTask("C").Status)
+	assert.Nil(t, h.GetTask("C").CompletedAt)
+
+	// Undone events aren't selected again: the next undo reaches C's creation
+	plan, err = h.PlanUndoLast(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), plan.Steps[0].Event.Seq)
+	assert.IsType(t, &TaskDeleted{}, plan.Steps[0].Compensation)
+	assert.NoError(t, h.Undo(plan))
+	assert.Nil(t, h.GetTask("C"))
+
+	// A parent goes together with its subtasks
+	plan, err = h.PlanUndoUntil(1)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Steps, 2)
+	assert.NoError(t, h.Undo(plan))
+	assert.Empty(t, h.GetTasks())
+
+	// Nothing was removed from the log
+	assert.Len(t, h.engine.GetEvents(), 4+2+2+3)
+	assert.Empty(t, h.UndoableEvents())
+	_, err = h.PlanUndoLast(1)
+	assert.Error(t, err)
+}
+
+// TestUndo_RefusesSideEffects tests that undo refuses a range containing an event that ran an agent
+func TestUndo_RefusesSideEffects(t *testing.T) {
+	h, err := New(WithCaller(&MockClaudeCaller{}), WithOutput(io.Discard))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	h.Engine().SetEvents([]atmos.Event{
+		&TaskCreated{EventMeta: EventMeta{Seq: 1}, TaskID: "A", Title: "A", Time: now},
+		&NextTaskSelected{EventMeta: EventMeta{Seq: 2}, TaskID: "A", Time: now},
+		&TaskExecuted{EventMeta: EventMeta{Seq: 3}, TaskID: "A", Time: now},
+		&TaskCreated{EventMeta: EventMeta{Seq: 4}, TaskID: "B", Title: "B", Time: now},
+	})
+
+	plan, err := h.PlanUndoLast(1)
+	assert.NoError(t, err)
+	assert.IsType(t, &TaskDeleted{}, plan.Steps[0].Compensation)
+
+	_, err = h.PlanUndoLast(2)
+	assert.ErrorContains(t, err, "cannot undo event #3 (task_executed)")
+	_, err = h.PlanUndoUntil(1)
+	assert.Error(t, err)
+}
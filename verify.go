@@ -0,0 +1,74 @@
+package hearth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxFeedbackLength bounds the verification output fed back into the next prompt
+const maxFeedbackLength = 4000
+
+// VerifyCommand returns the verification command for a task: its own, inherited from
+// the nearest ancestor that set one, else the workspace default ("" = no verification)
+func VerifyCommand(taskID string, tasks map[string]*Task, cfg *Config) string {
+	for task := tasks[taskID]; task != nil; task = parentOf(task, tasks) {
+		if task.Verify != "" {
+			return task.Verify
+		}
+	}
+	return cfg.Verify.Command
+}
+
+// VerifyLogPath returns the output file for one verification run of a task
+func VerifyLogPath(workspaceDir, taskID string, attempt int) string {
+	return filepath.Join(workspaceDir, ".hearth", "verify", taskID, fmt.Sprintf("%d.log", attempt))
+}
+
+// RunVerification runs a verification command through the shell in workDir, timed by clock
+// A non-zero exit is reported through the result's ExitCode, not as an error
+func RunVerification(command, workDir string, timeout time.Duration, clock Clock) (*CallResult, error) {
+	result, err := runCommand(clock, "sh", []string{"-c", command}, workDir, nil, timeout)
+	if err != nil && result.ExitCode > 0 {
+		return result, nil
+	}
+	return result, err
+}
+
+// writeVerifyLog stores the combined output of a verification run
+func writeVerifyLog(workspaceDir, taskID string, attempt int, command string, result *CallResult) (string, error) {
+	path := VerifyLogPath(workspaceDir, taskID, attempt)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create verify directory: %w", err)
+	}
+
+	content := verifyLogHeader(command, result.ExitCode) + result.Output + result.Stderr
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write verify log: %w", err)
+	}
+	return path, nil
+}
+
+// verifyLogHeader starts a verification log: the command and its exit code
+func verifyLogHeader(command string, exitCode int) string {
+	return fmt.Sprintf("$ %s\nexit_code: %d\n\n", command, exitCode)
+}
+
+// readVerifyOutput returns the command output stored in a verification log, without the header
+func readVerifyOutput(path, command string, exitCode int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(string(data), verifyLogHeader(command, exitCode)), nil
+}
+
+// tail returns at most the last max bytes of s
+func tail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return "..." + s[len(s)-max:]
+}
@@ -0,0 +1,63 @@
+package hearth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVerification_RequeuesWithFeedback tests that a failed verification re-runs the task
+// with the command output in its prompt, and the task completes once it passes
+func TestVerification_RequeuesWithFeedback(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Build", Match: func(c hearthtest.Call) bool { return c.Attempt >= 2 }, Files: map[string]string{"done.txt": "ok"}},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("verify.command", "test -f done.txt || { echo missing done.txt; exit 3; }"))
+	ws.AddTask("Build", "", "")
+
+	ws.Run()
+
+	ws.AssertTree(`
+		✓ Build
+	`)
+	calls := ws.Agent.Calls()
+	assert.Len(t, calls, 2)
+	assert.NotContains(t, calls[0].Prompt, "FAILED VERIFICATION")
+	assert.Contains(t, calls[1].Prompt, "PREVIOUS ATTEMPT FAILED VERIFICATION")
+	assert.Contains(t, calls[1].Prompt, "missing done.txt")
+	assert.Contains(t, calls[1].Prompt, "exit code 3")
+	assert.Equal(t, 1, strings.Count(calls[1].Prompt, "exit code 3"))
+	assert.NotContains(t, calls[1].Prompt, "exit_code")
+
+	var exitCodes []int
+	for _, event := range ws.Hearth.Engine().GetEvents() {
+		if verified, ok := event.(*hearth.TaskVerified); ok {
+			exitCodes = append(exitCodes, verified.ExitCode)
+			assert.FileExists(t, verified.LogPath)
+		}
+	}
+	assert.Equal(t, []int{3, 0}, exitCodes)
+}
+
+// TestVerification_FailsAfterRetryLimit tests that subtasks inherit the command and
+// a task that keeps failing verification fails after verify.max_attempts
+func TestVerification_FailsAfterRetryLimit(t *testing.T) {
+	ws := hearthtest.NewWorkspace(t, []hearthtest.Rule{
+		{Title: "Parent", Subtasks: []hearthtest.Subtask{{Title: "Broken"}}},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("verify.max_attempts", "2"))
+	assert.NoError(t, ws.Hearth.Process(&hearth.TaskCreated{TaskID: "T-1", Title: "Parent", Verify: "false", Time: ws.Hearth.Now()}))
+	ws.AddTask("Unverified", "", "")
+
+	ws.Run()
+
+	ws.AssertTree(`
+		→ Parent
+		  ✗ Broken
+		✓ Unverified
+	`)
+	assert.Equal(t, []string{"Parent", "Broken", "Broken", "Unverified"}, ws.Agent.CalledTitles())
+}
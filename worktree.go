@@ -0,0 +1,162 @@
+package hearth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Isolation modes for git.isolation
+const (
+	IsolationNone = "none" // every task runs in the workspace
+	IsolationRoot = "root" // each root task and its subtasks share one worktree
+	IsolationTask = "task" // every task gets its own worktree, branched from its parent's
+)
+
+// WorktreesDir returns the directory holding isolated worktrees
+func WorktreesDir(workspaceDir string) string {
+	return filepath.Join(workspaceDir, ".hearth", "worktrees")
+}
+
+// WorktreePath returns the worktree owned by a task
+func WorktreePath(workspaceDir, owner string) string {
+	return filepath.Join(WorktreesDir(workspaceDir), owner)
+}
+
+// WorktreeBranch returns the branch checked out in a task's worktree
+func WorktreeBranch(owner string) string {
+	return "hearth/" + owner
+}
+
+// WorkspaceRoot returns the workspace that owns dir when dir is inside one of its worktrees
+// Agents running in a worktree call `hearth add` from there and must reach the real log
+func WorkspaceRoot(dir string) string {
+	marker := string(filepath.Separator) + filepath.Join(".hearth", "worktrees") + string(filepath.Separator)
+	if i := strings.Index(dir+string(filepath.Separator), marker); i >= 0 {
+		return dir[:i]
+	}
+	return dir
+}
+
+// WorktreeOwner returns the task whose worktree taskID runs in ("" without isolation)
+func WorktreeOwner(taskID string, tasks map[string]*Task, isolation string) string {
+	task := tasks[taskID]
+	if task == nil {
+		return ""
+	}
+
+	switch isolation {
+	case IsolationTask:
+		return taskID
+	case IsolationRoot:
+		for task.ParentID != nil && tasks[*task.ParentID] != nil {
+			task = tasks[*task.ParentID]
+		}
+		return task.ID
+	}
+	return ""
+}
+
+// ownsWorktree reports whether taskID owns an existing worktree that must be merged on completion
+func ownsWorktree(workspaceDir, taskID string, tasks map[string]*Task, isolation string) bool {
+	if WorktreeOwner(taskID, tasks, isolation) != taskID {
+		return false
+	}
+	_, err := os.Stat(WorktreePath(workspaceDir, taskID))
+	return err == nil
+}
+
+// TaskWorkDir returns the directory the agent works in for a task:
+// its owner's worktree when one exists, otherwise the workspace
+func TaskWorkDir(workspaceDir, taskID string, tasks map[string]*Task, isolation string) string {
+	owner := WorktreeOwner(taskID, tasks, isolation)
+	if owner == "" {
+		return workspaceDir
+	}
+	if _, err := os.Stat(WorktreePath(workspaceDir, owner)); err != nil {
+		return workspaceDir
+	}
+	return worktreeWorkDir(workspaceDir, owner)
+}
+
+// worktreeWorkDir maps the workspace into a worktree (the workspace may be a repository subdirectory)
+func worktreeWorkDir(workspaceDir, owner string) string {
+	prefix, _ := runGit(workspaceDir, "rev-parse", "--show-prefix")
+	return filepath.Join(WorktreePath(workspaceDir, owner), prefix)
+}
+
+// parentWorkDir returns where an owner's worktree branches from and merges back into:
+// the nearest ancestor's existing worktree, or the workspace
+func parentWorkDir(workspaceDir, owner string, tasks map[string]*Task, isolation string) string {
+	task := tasks[owner]
+	for task != nil && task.ParentID != nil {
+		parentID := *task.ParentID
+		if ancestor := WorktreeOwner(parentID, tasks, isolation); ancestor != "" && ancestor != owner {
+			if _, err := os.Stat(WorktreePath(workspaceDir, ancestor)); err == nil {
+				return worktreeWorkDir(workspaceDir, ancestor)
+			}
+		}
+		task = tasks[parentID]
+	}
+	return workspaceDir
+}
+
+// ensureWorktree creates the owner's worktree on its branch if it does not exist yet
+// A new branch starts at the HEAD of baseDir; returns the agent's working directory
+func ensureWorktree(workspaceDir, owner, baseDir string) (string, error) {
+	path := WorktreePath(workspaceDir, owner)
+	if _, err := os.Stat(path); err == nil {
+		return worktreeWorkDir(workspaceDir, owner), nil
+	}
+
+	if !IsGitRepo(workspaceDir) {
+		return "", fmt.Errorf("worktree isolation requires a git repository")
+	}
+
+	branch := WorktreeBranch(owner)
+	var args []string
+	if _, err := runGit(workspaceDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		// Branch left behind by an earlier conflict - pick up where it stopped
+		args = []string{"worktree", "add", "-q", path, branch}
+	} else {
+		base, err := GitHead(baseDir)
+		if err != nil {
+			return "", err
+		}
+		if base == "" {
+			return "", fmt.Errorf("worktree isolation requires at least one commit to branch from")
+		}
+		args = []string{"worktree", "add", "-q", "-b", branch, path, base}
+	}
+	if _, err := runGit(workspaceDir, args...); err != nil {
+		return "", fmt.Errorf("failed to create worktree for %s: %w", owner, err)
+	}
+
+	return worktreeWorkDir(workspaceDir, owner), nil
+}
+
+// MergeWorktree merges the owner's branch into targetDir and removes the worktree and branch
+// On conflict the merge is aborted, the worktree is kept and the conflicting files are returned
+func MergeWorktree(workspaceDir, owner, targetDir, title string) (string, []string, error) {
+	branch := WorktreeBranch(owner)
+	message := fmt.Sprintf("Merge %s\n\n%s: %s", title, TaskTrailer, owner)
+
+	args := append(gitIdentity(targetDir), "merge", "--no-ff", "--no-edit", "-m", message, branch)
+	if _, err := runGit(targetDir, args...); err != nil {
+		conflicts, _ := runGit(targetDir, "diff", "--name-only", "--diff-filter=U")
+		runGit(targetDir, "merge", "--abort")
+		return "", strings.Fields(conflicts), fmt.Errorf("failed to merge %s: %w", branch, err)
+	}
+
+	commit, err := GitHead(targetDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Best effort - a leftover worktree or branch does not undo the merge
+	runGit(workspaceDir, "worktree", "remove", "--force", WorktreePath(workspaceDir, owner))
+	runGit(workspaceDir, "branch", "-D", branch)
+
+	return commit, nil, nil
+}
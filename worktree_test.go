@@ -0,0 +1,99 @@
+package hearth_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fmizzell/hearth"
+	"github.com/fmizzell/hearth/hearthtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newGitWorkspace creates a workspace inside a git repository with one commit
+func newGitWorkspace(t *testing.T, rules []hearthtest.Rule) *hearthtest.Workspace {
+	ws := hearthtest.NewWorkspace(t, rules)
+	git(t, ws.Dir, "init", "-q")
+	git(t, ws.Dir, "commit", "-q", "--allow-empty", "-m", "initial")
+	return ws
+}
+
+// TestWorktree_RootIsolation tests that a root task and its subtasks run in one worktree
+// that is merged into the workspace and removed when the root completes
+func TestWorktree_RootIsolation(t *testing.T) {
+	ws := newGitWorkspace(t, []hearthtest.Rule{
+		{Title: "Feature", Subtasks: []hearthtest.Subtask{{Title: "Part A"}, {Title: "Part B"}}},
+		{Title: "Part A", Files: map[string]string{"a.txt": "a"}},
+		{Title: "Part B", Files: map[string]string{"b.txt": "b"}},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("git.isolation", hearth.IsolationRoot))
+	root := ws.AddTask("Feature", "", "")
+
+	ws.Run()
+
+	ws.AssertTree(`
+		✓ Feature
+		  ✓ Part A
+		  ✓ Part B
+	`)
+	worktree := hearth.WorktreePath(ws.Dir, root)
+	for _, call := range ws.Agent.Calls() {
+		assert.Equal(t, worktree, call.WorkDir, call.Title)
+	}
+	assert.FileExists(t, filepath.Join(ws.Dir, "a.txt"))
+	assert.FileExists(t, filepath.Join(ws.Dir, "b.txt"))
+	assert.NoDirExists(t, worktree)
+	assert.Empty(t, git(t, ws.Dir, "branch", "--list", hearth.WorktreeBranch(root)))
+	assert.Contains(t, git(t, ws.Dir, "log", "-1", "--format=%B"), "Hearth-Task: "+root)
+}
+
+// TestWorktree_ConflictBlocksParent tests that a subtask whose branch cannot be merged
+// into its parent's worktree fails with a MergeConflict and keeps the parent open
+func TestWorktree_ConflictBlocksParent(t *testing.T) {
+	var ws *hearthtest.Workspace
+	ws = newGitWorkspace(t, []hearthtest.Rule{
+		{Title: "Parent", Subtasks: []hearthtest.Subtask{{Title: "Edit"}}},
+		{
+			Title: "Edit",
+			// Someone else changes the same file in the parent's worktree meanwhile
+			Match: func(c hearthtest.Call) bool {
+				parent := hearth.WorktreePath(ws.Dir, "T-1")
+				assert.NoError(t, os.WriteFile(filepath.Join(parent, "shared.txt"), []byte("theirs"), 0644))
+				git(t, parent, "add", "shared.txt")
+				git(t, parent, "commit", "-q", "-m", "theirs")
+				return true
+			},
+			Files: map[string]string{"shared.txt": "ours"},
+		},
+	})
+	assert.NoError(t, ws.Hearth.Config().Set("git.isolation", hearth.IsolationTask))
+	ws.AddTask("Parent", "", "")
+
+	ws.Run()
+
+	ws.AssertTree(`
+		→ Parent
+		  ✗ Edit
+	`)
+	var conflict *hearth.MergeConflict
+	for _, event := range ws.Hearth.Engine().GetEvents() {
+		if c, ok := event.(*hearth.MergeConflict); ok {
+			conflict = c
+		}
+	}
+	if assert.NotNil(t, conflict) {
+		assert.Equal(t, "T-1.1", conflict.TaskID)
+		assert.Equal(t, []string{"shared.txt"}, conflict.Files)
+	}
+
+	// The conflicting worktree is kept for manual resolution
+	assert.DirExists(t, hearth.WorktreePath(ws.Dir, "T-1.1"))
+	assert.NoFileExists(t, filepath.Join(ws.Dir, "shared.txt"))
+}
+
+// TestWorkspaceRoot tests that directories inside a worktree resolve to the owning workspace
+func TestWorkspaceRoot(t *testing.T) {
+	assert.Equal(t, "/ws", hearth.WorkspaceRoot("/ws/.hearth/worktrees/T-1"))
+	assert.Equal(t, "/ws", hearth.WorkspaceRoot("/ws/.hearth/worktrees/T-1/sub/dir"))
+	assert.Equal(t, "/ws/other", hearth.WorkspaceRoot("/ws/other"))
+}